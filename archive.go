@@ -0,0 +1,244 @@
+package remote
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadAndExtractTarGz streams the gzipped tar archive at given url into destDir with configured reader
+// Entries escaping destDir, including symbolic links pointing outside of it and entries written through
+// a symbolic link, fail the extraction
+// MaxBytes option limits the size of the archive
+func (r *Reader) DownloadAndExtractTarGz(url, destDir string) error {
+	return r.DownloadAndExtractTarGzWithContext(context.Background(), url, destDir)
+}
+
+// DownloadAndExtractTarGzWithContext streams the gzipped tar archive at given url into destDir
+// with configured reader using given context
+func (r *Reader) DownloadAndExtractTarGzWithContext(ctx context.Context, url, destDir string) error {
+	r = r.forURL(url)
+	root, err := extractRoot(destDir)
+	if err != nil {
+		return err
+	}
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	r.trackProgress(resp)
+	body, err := r.body(resp)
+	if err != nil {
+		return err
+	}
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return wrapError(err, "can't decompress archive")
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapError(err, "can't read archive")
+		}
+		path, err := extractPath(root, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = extractFile(path, os.FileMode(header.Mode), tr)
+		case tar.TypeSymlink:
+			err = extractSymlink(root, path, header.Linkname)
+		default:
+			// hard links, devices and alike are not needed by release bundles
+			continue
+		}
+		if err != nil {
+			return wrapErrorf(err, "can't extract %q", header.Name)
+		}
+	}
+}
+
+// DownloadAndExtractZip downloads the zip archive at given url into a temporary file with configured reader
+// and extracts it into destDir, entries escaping destDir fail the extraction
+// MaxBytes option limits the size of the archive
+func (r *Reader) DownloadAndExtractZip(url, destDir string) error {
+	return r.DownloadAndExtractZipWithContext(context.Background(), url, destDir)
+}
+
+// DownloadAndExtractZipWithContext downloads the zip archive at given url into a temporary file
+// with configured reader using given context and extracts it into destDir
+func (r *Reader) DownloadAndExtractZipWithContext(ctx context.Context, url, destDir string) error {
+	r = r.forURL(url)
+	root, err := extractRoot(destDir)
+	if err != nil {
+		return err
+	}
+	// zip needs random access so the archive is downloaded first
+	tmp, err := ioutil.TempFile("", "remote-*.zip")
+	if err != nil {
+		return wrapError(err, "can't create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	r.trackProgress(resp)
+	body, err := r.body(resp)
+	if err != nil {
+		return err
+	}
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		return wrapError(err, "can't download archive")
+	}
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return wrapError(err, "can't read archive")
+	}
+	for _, file := range zr.File {
+		path, err := extractPath(root, file.Name)
+		if err != nil {
+			return err
+		}
+		if err = extractZipFile(root, path, file); err != nil {
+			return wrapErrorf(err, "can't extract %q", file.Name)
+		}
+	}
+	return nil
+}
+
+// extractZipFile extracts a single entry of a zip archive to path in root
+func extractZipFile(root, path string, file *zip.File) error {
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return os.MkdirAll(path, 0755)
+	case mode&os.ModeSymlink != 0:
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		target, err := ioutil.ReadAll(io.LimitReader(rc, 4<<10))
+		if err != nil {
+			return err
+		}
+		return extractSymlink(root, path, string(target))
+	case mode.IsRegular():
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return extractFile(path, mode, rc)
+	}
+	return nil
+}
+
+// extractRoot creates destDir if needed and returns its path with symbolic links resolved
+// to check paths of entries against the real location
+func extractRoot(destDir string) (string, error) {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", wrapError(err, "can't create destination")
+	}
+	root, err := filepath.EvalSymlinks(destDir)
+	return root, wrapError(err, "can't resolve destination")
+}
+
+// extractPath returns the path of an archive entry in root failing if it would end up outside of it
+// Entries going through a symbolic link extracted before fail too, as writing them would follow the link
+func extractPath(root, name string) (string, error) {
+	path := filepath.Join(root, name)
+	if !within(root, path) {
+		return "", fmt.Errorf("can't extract %q outside of destination", name)
+	}
+	rel, _ := filepath.Rel(root, path)
+	current := root
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, elem)
+		info, err := os.Lstat(current)
+		if err != nil {
+			// the rest doesn't exist yet
+			break
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("can't extract %q through symbolic link", name)
+		}
+	}
+	return path, nil
+}
+
+// within checks if path is destDir or inside of it
+func within(destDir, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(destDir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// extractFile writes the content of an archive entry to path with the permissions of given mode
+func extractFile(path string, mode os.FileMode, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractSymlink creates a symbolic link at path unless its target is outside of root
+func extractSymlink(root, path, target string) error {
+	if !within(root, resolveLink(filepath.Dir(path), target)) {
+		return fmt.Errorf("can't link to %q outside of destination", target)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(target, path)
+}
+
+// resolveLink returns the path target of a link in dir points to
+// following links on its way as the system would, unlike cleaning the joined path
+func resolveLink(dir, target string) string {
+	current := dir
+	if filepath.IsAbs(target) {
+		volume := filepath.VolumeName(target)
+		current, target = volume+string(filepath.Separator), target[len(volume):]
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(target), "/") {
+		switch elem {
+		case "", ".":
+		case "..":
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, elem)
+			if real, err := filepath.EvalSymlinks(current); err == nil {
+				current = real
+			}
+		}
+	}
+	return current
+}
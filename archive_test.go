@@ -0,0 +1,133 @@
+package remote
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// entry is an entry of a test archive, a symbolic link if link is set
+type entry struct {
+	name, content, link string
+}
+
+func tarGz(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(e.content))}
+		if e.link != "" {
+			header = &tar.Header{Name: e.name, Typeflag: tar.TypeSymlink, Linkname: e.link}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		content := e.content
+		header.SetMode(0644)
+		if e.link != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			content = e.link
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloadAndExtract(t *testing.T) {
+	tests := map[string]struct {
+		entries []entry
+		ok      bool
+	}{
+		"files and links": {[]entry{
+			{name: "bin/tool", content: "tool"},
+			{name: "bin/alias", link: "tool"},
+			{name: "tool", link: "bin/tool"},
+		}, true},
+		"parent dir":   {[]entry{{name: "../evil", content: "evil"}}, false},
+		"link outside": {[]entry{{name: "evil", link: "../outside"}}, false},
+		"write through link": {[]entry{
+			{name: "p", link: "."},
+			{name: "p/c", link: "../outside"},
+			{name: "p/c/evil", content: "evil"},
+		}, false},
+		"link through link": {[]entry{
+			{name: "p", link: "."},
+			{name: "evil", link: "p/../outside"},
+		}, false},
+	}
+	formats := map[string]struct {
+		archive func(*testing.T, []entry) []byte
+		extract func(r *Reader, url, destDir string) error
+	}{
+		"tar.gz": {tarGz, (*Reader).DownloadAndExtractTarGz},
+		"zip":    {zipped, (*Reader).DownloadAndExtractZip},
+	}
+	for format, f := range formats {
+		for name, tt := range tests {
+			t.Run(format+"/"+name, func(t *testing.T) {
+				archive := f.archive(t, tt.entries)
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					_, _ = w.Write(archive)
+				}))
+				defer srv.Close()
+				root := t.TempDir()
+				if err := os.MkdirAll(filepath.Join(root, "outside"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				dest := filepath.Join(root, "dest")
+				err := f.extract(NewReader(), srv.URL, dest)
+				if (err == nil) != tt.ok {
+					t.Fatalf("extract = %v", err)
+				}
+				for _, path := range []string{"evil", "outside/evil"} {
+					if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+						t.Errorf("%s is written outside of destination", path)
+					}
+				}
+				if !tt.ok {
+					return
+				}
+				for _, path := range []string{"bin/tool", "bin/alias", "tool"} {
+					if b, err := ioutil.ReadFile(filepath.Join(dest, path)); err != nil || string(b) != "tool" {
+						t.Errorf("%s = %q, %v", path, b, err)
+					}
+				}
+			})
+		}
+	}
+}
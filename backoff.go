@@ -0,0 +1,146 @@
+package remote
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultJitter is the fraction backoff delays are randomized by unless Jitter is given
+const defaultJitter = 0.2
+
+// Backoff option for remote reader waits between retries
+// Delay starts from base and doubles on every retry up to max, zero max means no cap
+// Defaults to no delay
+func Backoff(base, max time.Duration) Option {
+	return func(r *Reader) {
+		r.backoffBase = base
+		r.backoffMax = max
+	}
+}
+
+// Jitter option for remote reader randomizes each backoff delay by ±fraction of it
+// e.g. 0.2 turns a 1 second delay into anything between 0.8 and 1.2 seconds
+// Defaults to 0.2 so clients retrying after an outage don't hit the server in lockstep, 0 disables it
+func Jitter(fraction float64) Option { return func(r *Reader) { r.jitter = fraction } }
+
+// JitterSeed option for remote reader randomizes backoff delays with a source seeded by given value
+// so the delays are deterministic e.g. in tests
+func JitterSeed(seed int64) Option {
+	/* #nosec */
+	return func(r *Reader) { r.random = &lockedRand{rand: rand.New(rand.NewSource(seed))} }
+}
+
+// lockedRand is a random number generator safe for concurrent use
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// float64 returns a random number in [0.0,1.0) from the seeded source or the global one if there is none
+func (l *lockedRand) float64() float64 {
+	if l == nil {
+		/* #nosec */
+		return rand.Float64()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rand.Float64()
+}
+
+// RetryBudget option for remote reader stops retrying once given duration passed since the first attempt
+// A retry is not attempted either if waiting for it would exceed the budget
+// Attempts in flight are bounded by Timeout, not the budget
+func RetryBudget(budget time.Duration) Option { return func(r *Reader) { r.retryBudget = budget } }
+
+// exceedsBudget checks if a retry after given delay exceeds the retry budget since the first attempt
+func (r *Reader) exceedsBudget(began time.Time, delay time.Duration) bool {
+	return r.retryBudget > 0 && time.Since(began)+delay > r.retryBudget
+}
+
+// backoff returns the delay to wait before given retry, starting from 0
+func (r *Reader) backoff(retry uint) time.Duration {
+	if r.backoffBase <= 0 {
+		return 0
+	}
+	delay := r.backoffBase
+	for i := uint(0); i < retry && delay <= math.MaxInt64/2; i++ {
+		if r.backoffMax > 0 && delay >= r.backoffMax {
+			break
+		}
+		delay *= 2
+	}
+	if r.backoffMax > 0 && delay > r.backoffMax {
+		delay = r.backoffMax
+	}
+	if r.jitter > 0 {
+		delay += time.Duration(float64(delay) * r.jitter * (2*r.random.float64() - 1))
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// delay returns how long to wait before given retry, starting from 0
+// Retry-After header of the response is preferred over backoff, clamped to the backoff cap
+func (r *Reader) delay(retry uint, resp *http.Response) time.Duration {
+	wait, ok := retryAfter(resp)
+	if !ok {
+		return r.backoff(retry)
+	}
+	if r.backoffMax > 0 && wait > r.backoffMax {
+		return r.backoffMax
+	}
+	return wait
+}
+
+// retryAfter parses Retry-After header of the response in delta-seconds or HTTP-date form
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := time.Until(date); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// sleep waits for given duration unless the context is done before
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// exceedsDeadline checks if waiting given duration passes the deadline of the context
+func exceedsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Now().Add(d).After(deadline)
+}
@@ -0,0 +1,104 @@
+package remote
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Balancer picks the host for each request to a relative url
+type Balancer interface {
+	// Next returns the base url like https://host:port to send the next request to
+	Next() string
+}
+
+// WithHosts option for remote reader distributes requests to relative urls among given hosts in turn
+// Hosts are base urls like https://host:port
+func WithHosts(hosts []string) Option { return WithBalancer(RoundRobin(hosts...)) }
+
+// WithBalancer option for remote reader sends requests to relative urls to the hosts picked by given balancer
+// Every attempt picks a host so failed attempts, including connection errors, are retried against another one
+// A balanced request is attempted at least twice
+func WithBalancer(balancer Balancer) Option { return func(r *Reader) { r.balancer = balancer } }
+
+// RoundRobin returns a balancer picking given hosts in turn
+func RoundRobin(hosts ...string) Balancer {
+	return &roundRobin{hosts: append([]string(nil), hosts...)}
+}
+
+type roundRobin struct {
+	hosts []string
+	next  uint64
+}
+
+func (b *roundRobin) Next() string {
+	if len(b.hosts) == 0 {
+		return ""
+	}
+	i := atomic.AddUint64(&b.next, 1) - 1
+	return b.hosts[i%uint64(len(b.hosts))]
+}
+
+// Weighted returns a balancer picking hosts proportionally to their weights
+// spreading picks of a host evenly rather than in bursts, hosts with non-positive weights are never picked
+func Weighted(weights map[string]int) Balancer {
+	b := &weighted{}
+	for host, weight := range weights {
+		if weight > 0 {
+			b.hosts = append(b.hosts, &weightedHost{host: host, weight: weight})
+			b.total += weight
+		}
+	}
+	return b
+}
+
+// weighted is a smooth weighted round robin balancer
+type weighted struct {
+	mu    sync.Mutex
+	hosts []*weightedHost
+	total int
+}
+
+type weightedHost struct {
+	host    string
+	weight  int
+	current int
+}
+
+func (b *weighted) Next() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var best *weightedHost
+	for _, h := range b.hosts {
+		h.current += h.weight
+		if best == nil || h.current > best.current {
+			best = h
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	best.current -= b.total
+	return best.host
+}
+
+// isBalanced checks if given url is relative and balanced among hosts
+func (r *Reader) isBalanced(rawURL string) bool {
+	return r.balancer != nil && !isAbsURL(rawURL)
+}
+
+// resolveURL returns the url to send an attempt to
+func (r *Reader) resolveURL(rawURL string) string {
+	if !r.isBalanced(rawURL) {
+		return rawURL
+	}
+	host := r.balancer.Next()
+	return strings.TrimSuffix(host, "/") + "/" + strings.TrimPrefix(rawURL, "/")
+}
+
+// isAbsURL checks if given url has a scheme
+func isAbsURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.IsAbs()
+}
@@ -0,0 +1,36 @@
+package remote
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// BaseURL option for remote reader resolves relative urls given to the calls against given base url
+// e.g. Bytes("/v1/users") reads https://api.example.com/v1/users with base https://api.example.com
+// Absolute urls are used as is, hosts of WithHosts or WithBalancer take precedence over the base
+func BaseURL(base string) Option {
+	return func(r *Reader) {
+		u, err := url.Parse(base)
+		if err != nil {
+			r.err = wrapError(err, "can't parse base url")
+			return
+		}
+		if !u.IsAbs() {
+			r.err = fmt.Errorf("can't use relative base url %q", base)
+			return
+		}
+		r.baseURL = u
+	}
+}
+
+// withBase resolves given url against the base url if it's relative
+func (r *Reader) withBase(rawURL string) string {
+	if r.baseURL == nil || r.balancer != nil {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.IsAbs() {
+		return rawURL
+	}
+	return r.baseURL.ResolveReference(u).String()
+}
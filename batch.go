@@ -0,0 +1,39 @@
+package remote
+
+import (
+	"context"
+	"sync"
+)
+
+// BytesBatch reads bytes from given urls with configured reader, at most concurrency at a time
+// Results and errors are in the order of the urls, non-positive concurrency reads all at once
+func (r *Reader) BytesBatch(urls []string, concurrency int) ([][]byte, []error) {
+	return r.BytesBatchWithContext(context.Background(), urls, concurrency)
+}
+
+// BytesBatchWithContext reads bytes from given urls with configured reader using given context
+// at most concurrency at a time
+func (r *Reader) BytesBatchWithContext(ctx context.Context, urls []string, concurrency int) ([][]byte, []error) {
+	results := make([][]byte, len(urls))
+	errs := make([]error, len(urls))
+	if concurrency <= 0 || concurrency > len(urls) {
+		concurrency = len(urls)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = r.BytesWithContext(ctx, urls[i])
+			}
+		}()
+	}
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, errs
+}
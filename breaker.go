@@ -0,0 +1,103 @@
+package remote
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit of the host is open
+var ErrCircuitOpen = errors.New("circuit of the host is open")
+
+// CircuitBreaker option for remote reader stops sending requests to a host for the cooldown period
+// after given number of consecutive failures, which are connection errors and 5xx responses
+// Once the cooldown passes a single request probes the host, closing the circuit if it succeeds
+// and opening it again for another cooldown if it fails
+func CircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(r *Reader) {
+		if threshold <= 0 {
+			r.breaker = nil
+			return
+		}
+		r.breaker = &breaker{threshold: threshold, cooldown: cooldown, circuits: map[string]*circuit{}}
+	}
+}
+
+// breaker keeps a circuit for every host
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+}
+
+// circuit is the state of a host
+type circuit struct {
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// allow checks if a request to given url can be sent
+func (b *breaker) allow(rawURL string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host(rawURL)]
+	if !ok || !c.open {
+		return nil
+	}
+	if c.probing || time.Since(c.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	// half-open, let a single probe through
+	c.probing = true
+	return nil
+}
+
+// record records the result of a request to given url
+// A canceled request is not counted but ends a probe so another one can be sent
+func (b *breaker) record(rawURL string, resp *http.Response, err error, canceled bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	key := host(rawURL)
+	c, ok := b.circuits[key]
+	if !ok {
+		if canceled || !failed {
+			return
+		}
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	probe := c.probing
+	c.probing = false
+	switch {
+	case canceled:
+	case failed:
+		c.failures++
+		if probe || c.failures >= b.threshold {
+			c.open = true
+			c.openedAt = time.Now()
+		}
+	default:
+		delete(b.circuits, key)
+	}
+}
+
+// host returns the host of given url to key circuits
+func host(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
@@ -0,0 +1,51 @@
+package remote
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerIgnoresUnsentRequests(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer srv.Close()
+	var broken int32 = 1
+	fail := func() error {
+		if atomic.LoadInt32(&broken) == 1 {
+			return errors.New("broken")
+		}
+		return nil
+	}
+	tests := map[string]Option{
+		"token": BearerTokenFunc(func() (string, error) { return "token", fail() }),
+		"sign":  SignRequest(func(*http.Request) error { return fail() }),
+	}
+	for name, option := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&broken, 1)
+			atomic.StoreInt32(&requests, 0)
+			reader := NewReader(option, CircuitBreaker(1, time.Minute), Retry(3))
+			for i := 0; i < 3; i++ {
+				_, stats, err := reader.ReadWithStats(srv.URL)
+				if err == nil || errors.Is(err, ErrCircuitOpen) || stats.Attempts != 0 {
+					t.Fatalf("ReadWithStats() = %+v, %v", stats, err)
+				}
+			}
+			atomic.StoreInt32(&broken, 0)
+			resp, stats, err := reader.ReadWithStats(srv.URL)
+			if err != nil {
+				t.Fatalf("host is blamed for unsent requests: %v", err)
+			}
+			resp.Body.Close()
+			if stats.Attempts != 1 || atomic.LoadInt32(&requests) != 1 {
+				t.Errorf("%d attempts, %d requests", stats.Attempts, atomic.LoadInt32(&requests))
+			}
+		})
+	}
+}
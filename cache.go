@@ -0,0 +1,103 @@
+package remote
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheSize is the maximum number of entries cached unless CacheSize option is given
+const defaultCacheSize = 1024
+
+// Cache option for remote reader caches bodies read by Bytes, JSON and alike in memory for given duration
+// Responses with Cache-Control: no-store are not cached
+func Cache(ttl time.Duration) Option { return func(r *Reader) { r.cacheTTL = ttl } }
+
+// CacheSize option for remote reader limits the number of entries of the cache
+// evicting the least recently used ones, defaults to 1024
+func CacheSize(n int) Option { return func(r *Reader) { r.cacheSize = n } }
+
+// memoryCache is a concurrency safe LRU cache of bodies keyed by url
+type memoryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+// cacheEntry is an element of the LRU list of memoryCache
+type cacheEntry struct {
+	key     string
+	b       []byte
+	resp    *http.Response
+	expires time.Time
+}
+
+func newMemoryCache(ttl time.Duration, maxEntries int) *memoryCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheSize
+	}
+	return &memoryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// get returns a copy of the cached body for the key unless it's missing or expired
+func (c *memoryCache) get(key string) ([]byte, *http.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(elem)
+		return nil, nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return append([]byte(nil), entry.b...), entry.resp, true
+}
+
+// set caches a copy of the body for the key unless the response forbids storing it
+func (c *memoryCache) set(key string, b []byte, resp *http.Response) {
+	if resp != nil && hasCacheDirective(resp.Header, "no-store") {
+		return
+	}
+	entry := &cacheEntry{key: key, b: append([]byte(nil), b...), resp: resp, expires: time.Now().Add(c.ttl)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes given element, cache must be locked
+func (c *memoryCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// hasCacheDirective checks if Cache-Control header has given directive
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, d := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(d), directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
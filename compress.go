@@ -0,0 +1,106 @@
+package remote
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// BytesGunzip reads bytes from given url with configured reader and decompresses them as gzip
+// for .gz files served without Content-Encoding, MaxBytes option limits the decompressed size
+func (r *Reader) BytesGunzip(url string) ([]byte, error) {
+	return r.BytesGunzipWithContext(context.Background(), url)
+}
+
+// BytesGunzipWithContext reads bytes from given url with configured reader using given context
+// and decompresses them as gzip
+func (r *Reader) BytesGunzipWithContext(ctx context.Context, url string) ([]byte, error) {
+	r = r.forURL(url)
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	r.trackProgress(resp)
+	body, err := r.decompress(resp)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, wrapError(err, "can't decompress body as gzip")
+	}
+	defer zr.Close()
+	b, err := ioutil.ReadAll(r.limitBody(zr))
+	if err == ErrBodyTooLarge {
+		return nil, err
+	}
+	if err != nil {
+		return nil, wrapError(err, "can't decompress body as gzip")
+	}
+	return b, nil
+}
+
+// AcceptEncoding option for remote reader requests compressed responses with given encodings
+// Bodies read by Bytes and JSON are decompressed transparently for gzip and deflate,
+// other encodings like br can't be given and any other encoding the server responds with results in an error
+// Downloads to files and writers don't ask for compression so sizes, ranges and checksums refer to the content
+func AcceptEncoding(encodings ...string) Option {
+	return func(r *Reader) {
+		for _, encoding := range encodings {
+			// quality values like "gzip;q=0.8" are allowed
+			name := strings.ToLower(strings.TrimSpace(strings.Split(encoding, ";")[0]))
+			if !isSupportedEncoding(name) {
+				r.err = fmt.Errorf("can't accept unsupported content encoding %q", encoding)
+				return
+			}
+		}
+		r.acceptEncoding = strings.Join(encodings, ", ")
+	}
+}
+
+// isSupportedEncoding checks if given content encoding can be decompressed
+func isSupportedEncoding(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "deflate", "identity":
+		return true
+	}
+	return false
+}
+
+// decompress wraps body of the response according to its Content-Encoding
+// Only engages if compression was requested via AcceptEncoding option
+func (r *Reader) decompress(resp *http.Response) (io.Reader, error) {
+	if r.acceptEncoding == "" {
+		return resp.Body, nil
+	}
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		return gz, wrapError(err, "can't decompress gzip body")
+	case "deflate":
+		// deflate should be zlib wrapped but some servers send raw deflate data
+		br := bufio.NewReader(resp.Body)
+		if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			return zr, wrapError(err, "can't decompress deflate body")
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// isZlibHeader checks if given two bytes are a valid zlib header
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint(header[0])<<8|uint(header[1]))%31 == 0
+}
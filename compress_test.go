@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAcceptEncodingGzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte("payload"))
+	_ = zw.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Accept-Encoding") != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q", req.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+	b, err := NewReader(AcceptEncoding("gzip", "deflate")).Bytes(srv.URL)
+	if err != nil || string(b) != "payload" {
+		t.Errorf("Bytes() = %q, %v", b, err)
+	}
+}
+
+func TestAcceptEncodingUnsupported(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer srv.Close()
+	for _, encoding := range []string{"br;q=0.9", "zstd"} {
+		if _, err := NewReader(AcceptEncoding("gzip", encoding)).Bytes(srv.URL); err == nil {
+			t.Errorf("AcceptEncoding(%q) is accepted", encoding)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("%d requests are sent with an unsupported encoding", n)
+	}
+}
@@ -0,0 +1,42 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// BytesIfModified reads bytes from given url with configured reader
+// unless the content matches given etag or was not modified since given time
+// Empty etag and zero time are not sent, returns false with nil bytes if the content was not modified
+func (r *Reader) BytesIfModified(url, etag string, since time.Time) ([]byte, bool, error) {
+	return r.BytesIfModifiedWithContext(context.Background(), url, etag, since)
+}
+
+// BytesIfModifiedWithContext reads bytes from given url with configured reader using given context
+// unless the content matches given etag or was not modified since given time
+func (r *Reader) BytesIfModifiedWithContext(ctx context.Context, url, etag string,
+	since time.Time) ([]byte, bool, error) {
+	r = r.forURL(url)
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if !since.IsZero() {
+		header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+	resp, err := r.do(ctx, http.MethodGet, url, header, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, false, nil
+	case r.isSuccess(resp.StatusCode):
+		b, err := r.readBody(resp)
+		return b, true, err
+	default:
+		return nil, false, r.statusError(resp, url)
+	}
+}
@@ -0,0 +1,19 @@
+package remote
+
+import "context"
+
+// WithContext option for remote reader binds given context to all calls of the reader
+// e.g. a shutdown context of a background worker, once it's done in-flight and subsequent calls fail
+// Calls without a context use it as is, WithContext variants of the calls use their own context
+// and still fail once the bound one is done
+func WithContext(ctx context.Context) Option { return func(r *Reader) { r.ctx = ctx } }
+
+// withBase returns a context derived from given one which is also canceled once the base context is done
+func withBase(ctx, base context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(base, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,18 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// WithCookieJar option for remote reader keeps cookies set by responses in memory
+// sending them with the following requests of the reader
+func WithCookieJar() Option {
+	return func(r *Reader) {
+		// cookiejar.New never fails without options
+		r.jar, _ = cookiejar.New(nil)
+	}
+}
+
+// CookieJar option for remote reader uses given jar to store and send cookies
+func CookieJar(jar http.CookieJar) Option { return func(r *Reader) { r.jar = jar } }
@@ -0,0 +1,132 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// debugBodyLimit is the maximum number of bytes of a body dumped in debug mode
+const debugBodyLimit = 4 << 10
+
+// defaultRedactHeaders are the headers whose values are never dumped
+var defaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Debug option for remote reader dumps every request and response on the wire to given writer
+// Bodies are dumped up to 4KB, response bodies as they are read
+// Values of Authorization, Proxy-Authorization, Cookie, Set-Cookie and RedactHeaders are redacted
+func Debug(w io.Writer) Option { return func(r *Reader) { r.debug = &debugWriter{w: w} } }
+
+// RedactHeaders option for remote reader masks values of given headers in debug dumps
+// in addition to Authorization, Proxy-Authorization, Cookie and Set-Cookie, e.g. custom API key headers
+// Logs and errors of the reader don't include any headers and mask passwords of urls
+func RedactHeaders(keys ...string) Option {
+	return func(r *Reader) { r.redactHeaders = append(r.redactHeaders, keys...) }
+}
+
+// redact returns a copy of given header with values of sensitive headers masked
+func (r *Reader) redact(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, keys := range [][]string{defaultRedactHeaders, r.redactHeaders} {
+		for _, key := range keys {
+			if redacted.Get(key) != "" {
+				redacted.Set(key, "[REDACTED]")
+			}
+		}
+	}
+	return redacted
+}
+
+// redactURL returns given url with its password masked like errors of net/http do
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+// debugWriter serializes dumps of concurrent requests
+type debugWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *debugWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.w.Write(p)
+}
+
+// debugging is the innermost middleware dumping requests and responses
+func (r *Reader) debugging(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		r.dumpRequest(req)
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			fmt.Fprintf(r.debug, "< %s %s failed: %v\n\n", req.Method, req.URL.Redacted(), err)
+			return resp, err
+		}
+		r.dumpResponse(resp)
+		return resp, nil
+	})
+}
+
+// dumpRequest dumps headers of the request and the beginning of a copy of its body
+func (r *Reader) dumpRequest(req *http.Request) {
+	// body is not read without dumping it
+	redacted := req.Clone(req.Context())
+	redacted.Header = r.redact(req.Header)
+	dump, err := httputil.DumpRequestOut(redacted, false)
+	if err != nil {
+		fmt.Fprintf(r.debug, "> can't dump request: %v\n\n", err)
+		return
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			dump = append(dump, "[body can't be dumped]"...)
+		} else if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(io.LimitReader(body, debugBodyLimit))
+			body.Close()
+			dump = append(dump, b...)
+		}
+	}
+	fmt.Fprintf(r.debug, "%s\n\n", dump)
+}
+
+// dumpResponse dumps headers of the response and tees the beginning of its body while it's read
+func (r *Reader) dumpResponse(resp *http.Response) {
+	redacted := *resp
+	redacted.Header = r.redact(resp.Header)
+	dump, err := httputil.DumpResponse(&redacted, false)
+	if err != nil {
+		fmt.Fprintf(r.debug, "< can't dump response: %v\n\n", err)
+		return
+	}
+	fmt.Fprintf(r.debug, "%s", dump)
+	resp.Body = &teeBody{ReadCloser: resp.Body, w: r.debug, remaining: debugBodyLimit}
+}
+
+// teeBody writes the first bytes read from the body to w
+type teeBody struct {
+	io.ReadCloser
+	w         io.Writer
+	remaining int
+}
+
+func (t *teeBody) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if t.remaining > 0 && n > 0 {
+		written := n
+		if written > t.remaining {
+			written = t.remaining
+		}
+		_, _ = t.w.Write(p[:written])
+		t.remaining -= written
+	}
+	return n, err
+}
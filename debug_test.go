@@ -0,0 +1,47 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSecretsAreRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	down := httptest.NewServer(nil)
+	down.Close()
+	withPassword := func(url string) string { return strings.Replace(url, "http://", "http://user:secret-password@", 1) }
+	var logs, dump, failedDump bytes.Buffer
+	reader := NewReader(WithLogger(log.New(&logs, "", 0)), Debug(&dump),
+		Header("X-Api-Key", "secret-key"), RedactHeaders("X-Api-Key"))
+	_, err := reader.Bytes(withPassword(srv.URL))
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Bytes() = %v", err)
+	}
+	_, failedErr := NewReader(Debug(&failedDump)).Bytes(withPassword(down.URL))
+	if failedErr == nil {
+		t.Fatal("Bytes() of a closed server succeeds")
+	}
+	_, mirrorErr := reader.BytesFromMirrors([]string{withPassword(srv.URL), withPassword(down.URL)})
+	if mirrorErr == nil {
+		t.Fatal("BytesFromMirrors() succeeds")
+	}
+	outputs := map[string]string{"error": err.Error(), "error url": httpErr.URL, "log": logs.String(), "dump": dump.String(),
+		"failed request error": failedErr.Error(), "failed request dump": failedDump.String(), "mirror error": mirrorErr.Error()}
+	for name, output := range outputs {
+		if output == "" {
+			t.Errorf("%s is empty", name)
+		}
+		if strings.Contains(output, "secret") {
+			t.Errorf("%s leaks a secret: %s", name, output)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// XML reads bytes from given url with configured reader and decodes body into the destination
+func (r *Reader) XML(url string, dest interface{}) error {
+	return r.XMLWithContext(context.Background(), url, dest)
+}
+
+// XMLWithContext reads bytes from given url with configured reader using given context
+// and decodes body into the destination
+func (r *Reader) XMLWithContext(ctx context.Context, url string, dest interface{}) error {
+	return r.decode(ctx, url, dest, DecodeAsXML)
+}
+
+// DecodeAsXML decodes given reader into destination
+// assuming content is xml
+func DecodeAsXML(r io.Reader, dest interface{}) error {
+	err := xml.NewDecoder(r).Decode(dest)
+	if err == io.EOF {
+		return nil
+	}
+	return wrapError(err, "can't decode xml")
+}
+
+// CSVDelimiter option for remote reader sets the field delimiter of CSV method, defaults to comma
+func CSVDelimiter(delimiter rune) Option { return func(r *Reader) { r.csvDelimiter = delimiter } }
+
+// CSV reads bytes from given url with configured reader and decodes body as csv records
+func (r *Reader) CSV(url string) ([][]string, error) {
+	return r.CSVWithContext(context.Background(), url)
+}
+
+// CSVWithContext reads bytes from given url with configured reader using given context
+// and decodes body as csv records
+func (r *Reader) CSVWithContext(ctx context.Context, url string) ([][]string, error) {
+	r = r.forURL(url)
+	var records [][]string
+	err := r.decode(ctx, url, &records, func(body io.Reader, dest interface{}) error {
+		var err error
+		*dest.(*[][]string), err = decodeCSV(body, r.csvDelimiter)
+		return err
+	})
+	return records, err
+}
+
+// DecodeAsCSV decodes given reader into records
+// assuming content is comma separated values
+func DecodeAsCSV(r io.Reader) ([][]string, error) {
+	return decodeCSV(r, ',')
+}
+
+// decodeCSV decodes given reader into records with given delimiter, comma if zero
+func decodeCSV(r io.Reader, delimiter rune) ([][]string, error) {
+	reader := csv.NewReader(r)
+	if delimiter != 0 {
+		reader.Comma = delimiter
+	}
+	records, err := reader.ReadAll()
+	return records, wrapError(err, "can't decode csv")
+}
+
+// decoders are the decoders used by Decode for media types
+// text/csv is handled by the reader itself to use its delimiter
+var decoders = map[string]decoder{
+	"application/json": DecodeAsJSON,
+	"text/json":        DecodeAsJSON,
+	"application/xml":  DecodeAsXML,
+	"text/xml":         DecodeAsXML,
+}
+
+// Accept option for remote reader sends given media type in the Accept header of requests
+// e.g. "application/json", Decode falls back to it for responses without a Content-Type
+// Defaults to no Accept header
+func Accept(mediaType string) Option { return func(r *Reader) { r.accept = mediaType } }
+
+// Decode reads bytes from given url with configured reader and decodes body into the destination
+// choosing the decoder by Content-Type of the response among json, xml, csv and yaml if built with yaml tag
+// Destination of csv must be a *[][]string. Bodies in ISO-8859-1 charset are converted to UTF-8
+func (r *Reader) Decode(url string, dest interface{}) error {
+	return r.DecodeWithContext(context.Background(), url, dest)
+}
+
+// DecodeWithContext reads bytes from given url with configured reader using given context
+// and decodes body into the destination choosing the decoder by Content-Type of the response
+func (r *Reader) DecodeWithContext(ctx context.Context, url string, dest interface{}) error {
+	r = r.forURL(url)
+	return r.decodeWith(ctx, url, dest, r.chooseDecoder)
+}
+
+// chooseDecoder returns the decoder for the content type of the response
+func (r *Reader) chooseDecoder(resp *http.Response) (decoder, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" && r.accept != "" {
+		// assume the server sent what was asked for, the first one if there are several
+		contentType = strings.TrimSpace(strings.Split(r.accept, ",")[0])
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, wrapErrorf(err, "can't parse content type %q", contentType)
+	}
+	var dec decoder
+	switch {
+	case mediaType == "text/csv":
+		dec = func(body io.Reader, dest interface{}) error {
+			records, ok := dest.(*[][]string)
+			if !ok {
+				return fmt.Errorf("can't decode csv into %T, need *[][]string", dest)
+			}
+			var err error
+			*records, err = decodeCSV(body, r.csvDelimiter)
+			return err
+		}
+	case decoders[mediaType] != nil:
+		dec = decoders[mediaType]
+	case strings.HasSuffix(mediaType, "+json"):
+		dec = decoders["application/json"]
+	case strings.HasSuffix(mediaType, "+xml"):
+		dec = decoders["application/xml"]
+	case strings.HasSuffix(mediaType, "+yaml") && decoders["application/yaml"] != nil:
+		dec = decoders["application/yaml"]
+	default:
+		supported := []string{"text/csv"}
+		for mediaType := range decoders {
+			supported = append(supported, mediaType)
+		}
+		sort.Strings(supported)
+		return nil, fmt.Errorf("can't decode content type %q, supported are %s",
+			contentType, strings.Join(supported, ", "))
+	}
+	return withCharset(dec, params["charset"])
+}
+
+// withCharset wraps the decoder to convert a body in given charset to UTF-8
+func withCharset(dec decoder, charset string) (decoder, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return dec, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return func(body io.Reader, dest interface{}) error {
+			return dec(&latin1Reader{r: bufio.NewReader(body)}, dest)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+}
+
+// latin1Reader converts ISO-8859-1 to UTF-8
+type latin1Reader struct {
+	r       *bufio.Reader
+	pending []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	// stop at the end of buffered data not to block with bytes to return
+	for n < len(p) && (n == 0 || len(l.pending) > 0 || l.r.Buffered() > 0) {
+		if len(l.pending) > 0 {
+			copied := copy(p[n:], l.pending)
+			l.pending = l.pending[copied:]
+			n += copied
+			continue
+		}
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		var buf [2]byte
+		l.pending = buf[:utf8.EncodeRune(buf[:], rune(b))]
+	}
+	return n, nil
+}
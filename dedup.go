@@ -0,0 +1,58 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Deduplicate option for remote reader shares a single request between concurrent
+// Bytes, JSON and alike calls for the same url, each caller gets its own copy of the body
+// Shared request runs with the context of the call which started it, others stop waiting once their own one is done
+func Deduplicate() Option { return func(r *Reader) { r.flights = &flightGroup{} } }
+
+// flightGroup deduplicates concurrent body reads of the same url
+// in the manner of golang.org/x/sync/singleflight
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is an in-flight or completed body read
+type flight struct {
+	// done is closed once the read is completed
+	done chan struct{}
+	b    []byte
+	resp *http.Response
+	err  error
+}
+
+// do calls fn for given key unless a call for the key is already in flight, then waits for its result
+// or until given context is done
+func (g *flightGroup) do(ctx context.Context, key string,
+	fn func() ([]byte, *http.Response, error)) ([]byte, *http.Response, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		// callers must not share a mutable slice
+		return append([]byte(nil), c.b...), c.resp, c.err
+	}
+	c := &flight{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.b, c.resp, c.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+	return append([]byte(nil), c.b...), c.resp, c.err
+}
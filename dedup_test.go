@@ -0,0 +1,49 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeduplicateWaiterContext(t *testing.T) {
+	var requests int32
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			close(started)
+		}
+		time.Sleep(500 * time.Millisecond)
+		_, _ = w.Write([]byte("shared"))
+	}))
+	defer srv.Close()
+	reader := NewReader(Deduplicate())
+	leader := make(chan error, 1)
+	go func() {
+		b, err := reader.Bytes(srv.URL)
+		if err == nil && string(b) != "shared" {
+			err = errors.New("unexpected body " + string(b))
+		}
+		leader <- err
+	}()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	began := time.Now()
+	if _, err := reader.BytesWithContext(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter got %v instead of its deadline", err)
+	}
+	if waited := time.Since(began); waited > 300*time.Millisecond {
+		t.Errorf("waiter waited %v for the shared request", waited)
+	}
+	if err := <-leader; err != nil {
+		t.Errorf("leader got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d requests are sent instead of 1", n)
+	}
+}
@@ -0,0 +1,15 @@
+package remote
+
+import "net/http"
+
+// defaultReader is used by package level functions, configured with defaults of NewReader
+var defaultReader = NewReader()
+
+// Read returns response from given url with the default reader
+func Read(url string) (*http.Response, error) { return defaultReader.Read(url) }
+
+// Bytes reads bytes from given url with the default reader
+func Bytes(url string) ([]byte, error) { return defaultReader.Bytes(url) }
+
+// JSON reads bytes from given url with the default reader and decodes body into the destination
+func JSON(url string, dest interface{}) error { return defaultReader.JSON(url, dest) }
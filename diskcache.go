@@ -0,0 +1,200 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskCacheExt is the extension of the files of the disk cache
+const diskCacheExt = ".cache"
+
+// DiskCache option for remote reader caches bodies read by Bytes, JSON and alike as files in given directory
+// so they survive restarts, an entry is served as is for given duration and revalidated
+// with If-None-Match and If-Modified-Since afterwards if the response had an ETag or Last-Modified header
+// Expired entries are evicted when the reader is created, responses with Cache-Control: no-store are not cached
+func DiskCache(dir string, ttl time.Duration) Option {
+	return func(r *Reader) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			r.err = wrapError(err, "can't create cache directory")
+			return
+		}
+		r.diskCache = &diskCache{dir: dir, ttl: ttl}
+		r.diskCache.evict()
+	}
+}
+
+// diskCache stores bodies in files named by the hash of their url
+// A file starts with a line of json encoded diskEntry followed by the body
+type diskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// diskEntry describes a cached body
+type diskEntry struct {
+	URL        string      `json:"url"`
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Stored     time.Time   `json:"stored"`
+}
+
+// fresh checks if the entry can be served without revalidation
+func (e *diskEntry) fresh(ttl time.Duration) bool {
+	return time.Since(e.Stored) < ttl
+}
+
+// revalidatable checks if the entry has a validator to send in a conditional request
+func (e *diskEntry) revalidatable() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// response returns a response like the one the entry is stored from, with the body already read
+func (e *diskEntry) response() *http.Response {
+	resp := &http.Response{Status: e.Status, StatusCode: e.StatusCode, Header: e.Header, Body: http.NoBody}
+	if u, err := url.Parse(e.URL); err == nil {
+		resp.Request = &http.Request{Method: http.MethodGet, URL: u, Header: http.Header{}}
+	}
+	return resp
+}
+
+// path returns the path of the file of given url
+func (c *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+diskCacheExt)
+}
+
+// load returns the cached entry and body of given url, a missing or unreadable file is a miss
+func (c *diskCache) load(key string) (*diskEntry, []byte, bool) {
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, nil, false
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, nil, false
+	}
+	entry := &diskEntry{}
+	if err = json.Unmarshal(line, entry); err != nil || entry.URL == "" {
+		return nil, nil, false
+	}
+	b, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, nil, false
+	}
+	return entry, b, true
+}
+
+// store writes the body of given url along with its response unless the response forbids storing it
+func (c *diskCache) store(key string, b []byte, resp *http.Response) error {
+	if hasCacheDirective(resp.Header, "no-store") {
+		return nil
+	}
+	entry := diskEntry{URL: key, Status: resp.Status, StatusCode: resp.StatusCode, Header: resp.Header}
+	if resp.Request != nil && resp.Request.URL != nil {
+		entry.URL = resp.Request.URL.String()
+	}
+	return c.write(key, entry, b)
+}
+
+// touch marks the entry of given url as fresh again after a successful revalidation
+func (c *diskCache) touch(key string, entry *diskEntry, b []byte) error {
+	return c.write(key, *entry, b)
+}
+
+// write replaces the file of given url with the entry stored now and the body
+func (c *diskCache) write(key string, entry diskEntry, b []byte) error {
+	entry.Stored = time.Now()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return wrapError(err, "can't encode cache entry")
+	}
+	return withTempFile(c.path(key), func(f *os.File) error {
+		_, err := f.Write(append(append(line, '\n'), b...))
+		return wrapError(err, "can't write cache entry")
+	})
+}
+
+// evict removes the entries which expired and can't be revalidated
+// or weren't revalidated for another ttl after expiring
+func (c *diskCache) evict() {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, diskCacheExt) {
+			continue
+		}
+		path := filepath.Join(c.dir, name)
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		line, err := bufio.NewReader(f).ReadBytes('\n')
+		f.Close()
+		entry := &diskEntry{}
+		if err != nil || json.Unmarshal(line, entry) != nil {
+			os.Remove(path)
+			continue
+		}
+		if !entry.fresh(c.ttl) && (!entry.revalidatable() || !entry.fresh(2*c.ttl)) {
+			os.Remove(path)
+		}
+	}
+}
+
+// diskBytes reads the whole body of given url through the disk cache
+func (r *Reader) diskBytes(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	entry, cached, ok := r.diskCache.load(url)
+	if ok && entry.fresh(r.diskCache.ttl) {
+		return cached, entry.response(), nil
+	}
+	if !ok || !entry.revalidatable() {
+		b, resp, err := r.sharedBytes(ctx, url)
+		if err == nil {
+			// caching is best effort, failing to store doesn't fail the read
+			_ = r.diskCache.store(url, b, resp)
+		}
+		return b, resp, err
+	}
+	header := http.Header{}
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if modified := entry.Header.Get("Last-Modified"); modified != "" {
+		header.Set("If-Modified-Since", modified)
+	}
+	resp, err := r.do(ctx, http.MethodGet, url, header, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		_ = r.diskCache.touch(url, entry, cached)
+		return cached, entry.response(), nil
+	case r.isSuccess(resp.StatusCode):
+		b, err := r.readBody(resp)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = r.diskCache.store(url, b, resp)
+		return b, resp, nil
+	default:
+		return nil, resp, r.statusError(resp, url)
+	}
+}
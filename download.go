@@ -0,0 +1,338 @@
+package remote
+
+import (
+	"context"
+	"crypto/md5"  // #nosec
+	"crypto/sha1" // #nosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadTo streams body of given url into the file at path with configured reader
+// Body is written to a temporary file first which is renamed to path on success
+// so a failed download never leaves a partial file behind
+func (r *Reader) DownloadTo(url, path string) error {
+	return r.DownloadToWithContext(context.Background(), url, path)
+}
+
+// DownloadToWithContext streams body of given url into the file at path with configured reader using given context
+func (r *Reader) DownloadToWithContext(ctx context.Context, url, path string) error {
+	return r.downloadFile(ctx, url, path, nil, "")
+}
+
+// DownloadToVerified streams body of given url into the file at path with configured reader
+// verifying its checksum with given algorithm, one of sha256, sha512, sha1 and md5,
+// against the expected hex encoded sum. No file is left behind if the checksum doesn't match
+func (r *Reader) DownloadToVerified(url, path, algo, expected string) error {
+	return r.DownloadToVerifiedWithContext(context.Background(), url, path, algo, expected)
+}
+
+// DownloadToVerifiedWithContext streams body of given url into the file at path with configured reader
+// using given context verifying its checksum with given algorithm against the expected hex encoded sum
+func (r *Reader) DownloadToVerifiedWithContext(ctx context.Context, url, path, algo, expected string) error {
+	h, err := newHash(algo)
+	if err != nil {
+		return err
+	}
+	return r.downloadFile(ctx, url, path, h, expected)
+}
+
+// downloadFile streams body of given url into a temporary file renamed to path on success
+// verifying the checksum against the expected one if a hash is given
+func (r *Reader) downloadFile(ctx context.Context, url, path string, h hash.Hash, expected string) error {
+	return withTempFile(path, func(tmp *os.File) error {
+		var w io.Writer = tmp
+		if h != nil {
+			w = io.MultiWriter(tmp, h)
+		}
+		if err := r.DownloadToWriterWithContext(ctx, url, w); err != nil {
+			return err
+		}
+		if h == nil {
+			return nil
+		}
+		if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, expected) {
+			return fmt.Errorf("checksum mismatch: got %s, expected %s", sum, expected)
+		}
+		return nil
+	})
+}
+
+// withTempFile calls write with a temporary file next to path which is renamed to path on success
+// so a failure never leaves a partial file behind
+func withTempFile(path string, write func(*os.File) error) (err error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return wrapError(err, "can't create temporary file")
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err = write(tmp); err != nil {
+		return err
+	}
+	// temporary files are private, use the usual permissions of a created file instead
+	if err = tmp.Chmod(0644); err != nil {
+		return wrapError(err, "can't change mode of temporary file")
+	}
+	if err = tmp.Close(); err != nil {
+		return wrapError(err, "can't close temporary file")
+	}
+	return wrapError(os.Rename(tmp.Name(), path), "can't move downloaded file")
+}
+
+// newHash returns the hash of given checksum algorithm
+func newHash(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "sha1":
+		/* #nosec */
+		return sha1.New(), nil
+	case "md5":
+		/* #nosec */
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", algo)
+	}
+}
+
+// downloading returns a copy of the reader without AcceptEncoding for downloading content as is
+// so sizes, ranges and checksums refer to the content itself
+// Transport still decompresses gzip transparently unless a range is requested
+func (r *Reader) downloading() *Reader {
+	if r.acceptEncoding == "" {
+		return r
+	}
+	download := *r
+	download.acceptEncoding = ""
+	return &download
+}
+
+// DownloadToWriter streams body of given url into the writer with configured reader
+func (r *Reader) DownloadToWriter(url string, w io.Writer) error {
+	return r.DownloadToWriterWithContext(context.Background(), url, w)
+}
+
+// DownloadToWriterWithContext streams body of given url into the writer with configured reader using given context
+func (r *Reader) DownloadToWriterWithContext(ctx context.Context, url string, w io.Writer) error {
+	r = r.forURL(url).downloading()
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	r.trackProgress(resp)
+	_, err = io.Copy(w, resp.Body)
+	return wrapError(err, "can't download body of response")
+}
+
+// ResumeDownload downloads given url into the file at path with configured reader
+// continuing from the end of an existing partial file with a range request
+// Whole content is downloaded again if the server doesn't support ranges,
+// final size of the file is verified if the server tells it
+func (r *Reader) ResumeDownload(url, path string) error {
+	return r.ResumeDownloadWithContext(context.Background(), url, path)
+}
+
+// ResumeDownloadWithContext downloads given url into the file at path with configured reader using given context
+// continuing from the end of an existing partial file with a range request
+func (r *Reader) ResumeDownloadWithContext(ctx context.Context, url, path string) error {
+	r = r.forURL(url).downloading()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return wrapError(err, "can't open file")
+	}
+	defer file.Close()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return wrapError(err, "can't seek file")
+	}
+	if offset == 0 {
+		return r.restartDownload(ctx, url, file)
+	}
+	resp, err := r.ReadRangeWithContext(ctx, url, offset, -1)
+	switch {
+	case err == ErrRangeIgnored:
+		defer resp.Body.Close()
+		if err = truncate(file); err != nil {
+			return err
+		}
+		return r.writeBody(resp, file, resp.ContentLength)
+	case isStatus(err, http.StatusRequestedRangeNotSatisfiable):
+		// file may be already complete
+		if size, err := r.contentLength(ctx, url); err == nil && size == offset {
+			return nil
+		}
+		return r.restartDownload(ctx, url, file)
+	case err != nil:
+		return err
+	}
+	start, _, size, err := ParseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	if start != offset {
+		// appending another part than asked for would corrupt the file
+		drain(resp.Body)
+		return r.restartDownload(ctx, url, file)
+	}
+	defer resp.Body.Close()
+	return r.writeBody(resp, file, size)
+}
+
+// restartDownload writes the whole body of given url into the file replacing its content
+func (r *Reader) restartDownload(ctx context.Context, url string, file *os.File) error {
+	if err := truncate(file); err != nil {
+		return err
+	}
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return r.writeBody(resp, file, resp.ContentLength)
+}
+
+// writeBody appends the body of the response to the file verifying its final size unless it's negative
+func (r *Reader) writeBody(resp *http.Response, file *os.File, size int64) error {
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return wrapError(err, "can't download body of response")
+	}
+	if size < 0 {
+		return nil
+	}
+	written, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return wrapError(err, "can't seek file")
+	}
+	if written != size {
+		return fmt.Errorf("downloaded %d bytes instead of %d", written, size)
+	}
+	return nil
+}
+
+// contentLength returns the size of the content at given url via a HEAD request, -1 if unknown
+func (r *Reader) contentLength(ctx context.Context, url string) (int64, error) {
+	resp, err := r.HeadWithContext(ctx, url)
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Body.Close()
+	if !r.isSuccess(resp.StatusCode) {
+		return -1, r.statusError(resp, url)
+	}
+	return resp.ContentLength, nil
+}
+
+// truncate empties the file to write it from the start
+func truncate(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return wrapError(err, "can't truncate file")
+	}
+	_, err := file.Seek(0, io.SeekStart)
+	return wrapError(err, "can't seek file")
+}
+
+// DownloadParallel downloads given url into the file at path with configured reader
+// fetching given number of chunks concurrently with range requests
+// Falls back to a single stream if the server doesn't advertise range support and the size of the content
+// or doesn't serve the ranges asked for
+func (r *Reader) DownloadParallel(url, path string, chunks int) error {
+	return r.DownloadParallelWithContext(context.Background(), url, path, chunks)
+}
+
+// DownloadParallelWithContext downloads given url into the file at path with configured reader using given context
+// fetching given number of chunks concurrently with range requests
+func (r *Reader) DownloadParallelWithContext(ctx context.Context, url, path string, chunks int) error {
+	r = r.forURL(url).downloading()
+	resp, err := r.HeadWithContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	size := resp.ContentLength
+	if chunks < 2 || size <= 0 || !r.isSuccess(resp.StatusCode) ||
+		!strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes") {
+		return r.DownloadToWithContext(ctx, url, path)
+	}
+	if int64(chunks) > size {
+		chunks = int(size)
+	}
+	chunkSize := (size + int64(chunks) - 1) / int64(chunks)
+	err = withTempFile(path, func(tmp *os.File) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		errs := make(chan error, chunks)
+		var started int
+		for start := int64(0); start < size; start += chunkSize {
+			end := start + chunkSize - 1
+			if end >= size {
+				end = size - 1
+			}
+			started++
+			go func(start, end int64) { errs <- r.downloadChunk(ctx, url, tmp, start, end) }(start, end)
+		}
+		var firstErr error
+		for i := 0; i < started; i++ {
+			if err := <-errs; err != nil && firstErr == nil {
+				firstErr = err
+				// stop the other chunks
+				cancel()
+			}
+		}
+		return firstErr
+	})
+	if errors.Is(err, ErrRangeIgnored) {
+		// ranges are advertised but not served
+		return r.DownloadToWithContext(ctx, url, path)
+	}
+	return err
+}
+
+// downloadChunk writes bytes from start to end inclusive of given url at the same offset of the file
+func (r *Reader) downloadChunk(ctx context.Context, url string, file *os.File, start, end int64) error {
+	resp, err := r.ReadRangeWithContext(ctx, url, start, end)
+	if err == ErrRangeIgnored {
+		drain(resp.Body)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	first, last, _, err := ParseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	if first != start || last != end {
+		// writing another part than asked for would corrupt the file
+		drain(resp.Body)
+		return ErrRangeIgnored
+	}
+	defer resp.Body.Close()
+	written, err := io.Copy(io.NewOffsetWriter(file, start), io.LimitReader(resp.Body, end-start+1))
+	if err != nil {
+		return wrapError(err, "can't download chunk")
+	}
+	if written != end-start+1 {
+		return fmt.Errorf("downloaded %d bytes instead of %d for chunk at %d", written, end-start+1, start)
+	}
+	return nil
+}
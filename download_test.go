@@ -0,0 +1,129 @@
+package remote
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResumeDownload(t *testing.T) {
+	content := []byte("0123456789")
+	tests := map[string]http.HandlerFunc{
+		"range": func(w http.ResponseWriter, req *http.Request) {
+			http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+		},
+		"wrong start": func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Range") == "" {
+				_, _ = w.Write(content)
+				return
+			}
+			// ignores the asked range yet claims a partial response
+			w.Header().Set("Content-Range", "bytes 0-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content)
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+			path := filepath.Join(t.TempDir(), "file")
+			if err := ioutil.WriteFile(path, content[:5], 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := NewReader().ResumeDownload(srv.URL, path); err != nil {
+				t.Errorf("ResumeDownload() = %v", err)
+			}
+			if b, _ := ioutil.ReadFile(path); !bytes.Equal(b, content) {
+				t.Errorf("file is %q instead of %q", b, content)
+			}
+		})
+	}
+}
+
+// gzipServer serves given content gzipped whenever the client accepts it and no range is asked for
+func gzipServer(content []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") || req.Header.Get("Range") != "" {
+			http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write(content)
+		_ = zw.Close()
+	}))
+}
+
+func TestDownloadWithAcceptEncoding(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	srv := gzipServer(content)
+	defer srv.Close()
+	sum := sha256.Sum256(content)
+	reader := NewReader(AcceptEncoding("gzip"))
+	downloads := map[string]func(path string) error{
+		"to": func(path string) error { return reader.DownloadTo(srv.URL, path) },
+		"verified": func(path string) error {
+			return reader.DownloadToVerified(srv.URL, path, "sha256", hex.EncodeToString(sum[:]))
+		},
+		"resume":   func(path string) error { return reader.ResumeDownload(srv.URL, path) },
+		"parallel": func(path string) error { return reader.DownloadParallel(srv.URL, path, 3) },
+	}
+	for name, download := range downloads {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file")
+			if err := download(path); err != nil {
+				t.Fatalf("download = %v", err)
+			}
+			if b, _ := ioutil.ReadFile(path); !bytes.Equal(b, content) {
+				t.Errorf("file has %d bytes instead of the content", len(b))
+			}
+		})
+	}
+}
+
+func TestDownloadParallel(t *testing.T) {
+	content := []byte("0123456789")
+	serve := func(w http.ResponseWriter, req *http.Request) {
+		http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+	}
+	tests := map[string]http.HandlerFunc{
+		"range": serve,
+		"wrong range": func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Range") == "" {
+				serve(w, req)
+				return
+			}
+			// claims to serve the whole content as a partial response whatever is asked for
+			w.Header().Set("Content-Range", "bytes 0-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content)
+		},
+		"ignored range": func(w http.ResponseWriter, req *http.Request) {
+			// advertises ranges but always sends the whole content
+			req.Header.Del("Range")
+			serve(w, req)
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+			path := filepath.Join(t.TempDir(), "file")
+			if err := NewReader().DownloadParallel(srv.URL, path, 2); err != nil {
+				t.Fatalf("DownloadParallel() = %v", err)
+			}
+			if b, _ := ioutil.ReadFile(path); !bytes.Equal(b, content) {
+				t.Errorf("file is %q instead of %q", b, content)
+			}
+		})
+	}
+}
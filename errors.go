@@ -0,0 +1,142 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+)
+
+// Sentinel errors matched by errors.Is against errors of the reader
+// Status based ones are matched by HTTPError, e.g. errors.Is(err, ErrNotFound) for 404 and 410
+var (
+	// ErrNotFound matches 404 Not Found and 410 Gone
+	ErrNotFound = errors.New("not found")
+	// ErrTimeout matches timed out requests as well as 408 Request Timeout and 504 Gateway Timeout
+	ErrTimeout = errors.New("timeout")
+	// ErrTooManyRequests matches 429 Too Many Requests
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrServerError matches any 5xx status
+	ErrServerError = errors.New("server error")
+)
+
+// wrapError annotates err with given message keeping it available to errors.Is and errors.As
+// nil is returned as is
+func wrapError(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// wrapErrorf annotates err with given formatted message keeping it available to errors.Is and errors.As
+func wrapErrorf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+}
+
+// defaultErrorBodyLimit is the maximum number of bytes of a response body captured into HTTPError
+// unless ErrorBodyLimit option is given
+const defaultErrorBodyLimit = 4 << 10
+
+// ErrorBodyLimit option for remote reader sets the maximum number of bytes of a response body
+// captured into HTTPError, longer bodies are cut and marked as truncated. Defaults to 4 KB, 0 captures nothing
+func ErrorBodyLimit(n int) Option { return func(r *Reader) { r.errorBodyLimit = n } }
+
+// HTTPError is returned when a response has an unexpected status
+// Can be extracted from returned errors via errors.As
+type HTTPError struct {
+	// URL is the requested url with its password masked
+	URL        string
+	StatusCode int
+	Status     string
+	// Body is the beginning of the response body, limited in size
+	Body []byte
+	// Truncated tells if Body is cut at the limit
+	Truncated bool
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("Got %q: can't read given url %q", e.Status, e.URL)
+}
+
+// Is matches the sentinel errors by status code
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrTimeout:
+		return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500 && e.StatusCode < 600
+	}
+	return false
+}
+
+// timeoutError marks a timed out request to match ErrTimeout while keeping the original error
+type timeoutError struct {
+	err error
+}
+
+func (e *timeoutError) Error() string { return e.err.Error() }
+
+func (e *timeoutError) Unwrap() error { return e.err }
+
+func (e *timeoutError) Is(target error) bool { return target == ErrTimeout }
+
+// statusError returns the error for an unexpected status of the response
+// capturing the beginning of its body, which should still be open
+// The rest of the body is discarded up to a limit so the connection can be reused once it's closed
+func (r *Reader) statusError(resp *http.Response, url string) error {
+	var body []byte
+	truncated := false
+	if r.errorBodyLimit > 0 {
+		// one more byte tells if there is more
+		body, _ = ioutil.ReadAll(io.LimitReader(resp.Body, int64(r.errorBodyLimit)+1))
+		if len(body) > r.errorBodyLimit {
+			body, truncated = body[:r.errorBodyLimit], true
+		}
+	}
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, drainLimit))
+	return &HTTPError{
+		URL:        redactURL(url),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+		Truncated:  truncated,
+	}
+}
+
+// retryError returns the error of an attempt which is going to be retried
+func (r *Reader) retryError(resp *http.Response, err error, url string) error {
+	if err != nil {
+		return err
+	}
+	return r.statusError(resp, url)
+}
+
+// isTimeoutErr checks if given error is a timeout
+// either a net.Error timing out or an exceeded context deadline anywhere in the chain
+func isTimeoutErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isStatus checks if given error is an HTTPError with given status code
+func isStatus(err error, code int) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == code
+}
@@ -0,0 +1,106 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowServer responds after given delay unless the request is canceled first
+func slowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+}
+
+func TestWrapErrorKeepsChain(t *testing.T) {
+	err := wrapErrorf(wrapError(context.DeadlineExceeded, "can't get url"), "can't read %q", "url")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(%v, context.DeadlineExceeded) = false", err)
+	}
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")}
+	var netErr net.Error
+	if err = wrapError(opErr, "can't get url"); !errors.As(err, &netErr) || netErr != opErr {
+		t.Errorf("errors.As(%v, *net.Error) didn't find the original error", err)
+	}
+	if wrapError(nil, "message") != nil || wrapErrorf(nil, "message %d", 1) != nil {
+		t.Error("nil error is wrapped")
+	}
+}
+
+func TestContextDeadlineIsKept(t *testing.T) {
+	srv := slowServer(time.Second)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewReader().BytesWithContext(ctx, srv.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(%v, context.DeadlineExceeded) = false", err)
+	}
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is(%v, ErrTimeout) = false", err)
+	}
+}
+
+func TestNetErrorIsKept(t *testing.T) {
+	srv := slowServer(time.Second)
+	defer srv.Close()
+	_, err := NewReader(Timeout(50 * time.Millisecond)).Bytes(srv.URL)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("errors.As(%v, *net.Error) didn't find a timeout", err)
+	}
+}
+
+// timeoutNetErr is a bare net.Error timing out
+type timeoutNetErr struct{}
+
+func (timeoutNetErr) Error() string   { return "i/o timeout" }
+func (timeoutNetErr) Timeout() bool   { return true }
+func (timeoutNetErr) Temporary() bool { return true }
+
+func TestTimeoutIsRetried(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		timeout bool
+	}{
+		{"url error", &url.Error{Op: "Get", URL: "http://example.com", Err: timeoutNetErr{}}, true},
+		{"net error", timeoutNetErr{}, true},
+		{"context deadline", fmt.Errorf("can't read body: %w", context.DeadlineExceeded), true},
+		{"other error", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if isTimeoutErr(tt.err) != tt.timeout {
+				t.Errorf("isTimeoutErr(%v) = %v", tt.err, !tt.timeout)
+			}
+			var attempts int32
+			reader := NewReader(Retry(2), Backoff(time.Millisecond, time.Millisecond),
+				Transport(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					if atomic.AddInt32(&attempts, 1) == 1 {
+						return nil, tt.err
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+				})))
+			_, err := reader.Bytes("http://example.com")
+			if retried := atomic.LoadInt32(&attempts) == 2; retried != tt.timeout {
+				t.Errorf("retried = %v, error %v", retried, err)
+			}
+			if tt.timeout && err != nil {
+				t.Errorf("Bytes() = %v after retry", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+)
+
+// Head returns response of a HEAD request to given url with configured reader
+// Response has no body to read but it still needs to be closed
+func (r *Reader) Head(url string) (*http.Response, error) {
+	return r.HeadWithContext(context.Background(), url)
+}
+
+// HeadWithContext returns response of a HEAD request to given url with configured reader using given context
+func (r *Reader) HeadWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return r.do(ctx, http.MethodHead, url, nil, nil)
+}
+
+// Exists checks if a resource exists at given url with a HEAD request
+// Any 2xx means it exists while 404 and 410 mean it doesn't, other statuses are returned as HTTPError
+func (r *Reader) Exists(url string) (bool, error) {
+	return r.ExistsWithContext(context.Background(), url)
+}
+
+// ExistsWithContext checks if a resource exists at given url with a HEAD request using given context
+func (r *Reader) ExistsWithContext(ctx context.Context, url string) (bool, error) {
+	r = r.forURL(url)
+	resp, err := r.HeadWithContext(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return false, nil
+	default:
+		return false, r.statusError(resp, url)
+	}
+}
@@ -0,0 +1,43 @@
+package remote
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HostConfig option for remote reader applies given options on top of the others to requests to given host
+// e.g. a different Timeout or BearerToken for one of the hosts, host may include a port to match only that
+// Requests are sent and their responses read by a separate reader for the host so options like RateLimit or Cache
+// aren't shared, while the ones for the body, e.g. MaxBytes, AcceptEncoding or Validate, apply to the host as well
+func HostConfig(host string, options ...Option) Option {
+	return func(r *Reader) {
+		if r.hostOptions == nil {
+			r.hostOptions = map[string][]Option{}
+		}
+		host = strings.ToLower(host)
+		r.hostOptions[host] = append(r.hostOptions[host], options...)
+	}
+}
+
+// hostReader returns the reader configured for the host of given url or nil if there is none
+func (r *Reader) hostReader(rawURL string) *Reader {
+	if r.hosts == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	if hr, ok := r.hosts[strings.ToLower(u.Host)]; ok {
+		return hr
+	}
+	return r.hosts[strings.ToLower(u.Hostname())]
+}
+
+// forURL returns the reader configured for the host of given url or the reader itself if there is none
+func (r *Reader) forURL(rawURL string) *Reader {
+	if hr := r.hostReader(r.withBase(rawURL)); hr != nil {
+		return hr
+	}
+	return r
+}
@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestHostConfigReadsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// transport asks for gzip on its own but never for deflate
+		if req.Header.Get("Accept-Encoding") == "deflate" {
+			w.Header().Set("Content-Encoding", "deflate")
+			zw := zlib.NewWriter(w)
+			_, _ = zw.Write([]byte("payload"))
+			_ = zw.Close()
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("payload"))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	invalid := errors.New("invalid")
+	tests := map[string]struct {
+		options []Option
+		err     error
+	}{
+		"accept encoding": {[]Option{AcceptEncoding("deflate")}, nil},
+		"success status":  {[]Option{SuccessStatus(http.StatusAccepted)}, nil},
+		"accept any 2xx":  {[]Option{AcceptAny2xx()}, nil},
+		"max bytes":       {[]Option{AcceptAny2xx(), MaxBytes(3)}, ErrBodyTooLarge},
+		"validate": {[]Option{AcceptAny2xx(), Validate(func(*http.Response, []byte) error {
+			return invalid
+		})}, invalid},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			reader := NewReader(HostConfig(u.Host, tt.options...))
+			b, err := reader.Bytes(srv.URL)
+			if !errors.Is(err, tt.err) || (err == nil && !bytes.Equal(b, []byte("payload"))) {
+				t.Errorf("Bytes() = %q, %v", b, err)
+			}
+		})
+	}
+}
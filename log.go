@@ -0,0 +1,28 @@
+package remote
+
+import (
+	"net/http"
+	"time"
+)
+
+// Logger is the minimal interface to log requests of a reader, satisfied by *log.Logger
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// WithLogger option for remote reader logs every attempt with its url, status and duration
+// Logging is off by default
+func WithLogger(logger Logger) Option { return func(r *Reader) { r.logger = logger } }
+
+// logAttempt logs the result of a request attempt if a logger is configured
+func (r *Reader) logAttempt(method, url string, attempt uint, resp *http.Response, err error, duration time.Duration) {
+	if r.logger == nil {
+		return
+	}
+	url = redactURL(url)
+	if err != nil {
+		r.logger.Printf("remote: %s %s attempt %d failed in %s: %v", method, url, attempt, duration, err)
+		return
+	}
+	r.logger.Printf("remote: %s %s attempt %d got %q in %s", method, url, attempt, resp.Status, duration)
+}
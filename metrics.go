@@ -0,0 +1,29 @@
+package remote
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsCollector observes every request attempt of a reader
+// to be adapted to Prometheus or any other metrics system
+type MetricsCollector interface {
+	// ObserveRequest is called after every attempt including retries
+	// Status is 0 if the attempt failed without a response
+	ObserveRequest(url string, status int, duration time.Duration, err error)
+}
+
+// WithMetrics option for remote reader reports every attempt to given collector
+func WithMetrics(collector MetricsCollector) Option { return func(r *Reader) { r.metrics = collector } }
+
+// observe reports the result of a request attempt if a collector is configured
+func (r *Reader) observe(url string, resp *http.Response, err error, duration time.Duration) {
+	if r.metrics == nil {
+		return
+	}
+	var status int
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	r.metrics.ObserveRequest(url, status, duration, err)
+}
@@ -0,0 +1,67 @@
+package remote
+
+import "net/http"
+
+// Middleware wraps a round tripper to intercept every outgoing request and its response
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to use an ordinary function as a round tripper
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// Transport option for remote reader sends requests through given round tripper
+// instead of the transport built by the reader, so transport options are ignored
+func Transport(rt http.RoundTripper) Option { return func(r *Reader) { r.roundTripper = rt } }
+
+// Use option for remote reader wraps the transport with given middlewares
+// First middleware is the outermost, seeing requests first and responses last
+func Use(middlewares ...Middleware) Option {
+	return func(r *Reader) { r.middlewares = append(r.middlewares, middlewares...) }
+}
+
+// wrap wraps given round tripper with configured middlewares and the debug dump as the innermost one
+func (r *Reader) wrap(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if r.debug != nil {
+		rt = r.debugging(rt)
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		rt = r.middlewares[i](rt)
+	}
+	return rt
+}
+
+// Tracer starts a client span for every outgoing request, e.g. an adapter of OpenTelemetry
+type Tracer interface {
+	// Start starts a span for the request and returns the request to send, carrying the span
+	// in its context and propagation headers like W3C traceparent, along with a function
+	// ending the span with the result of the request
+	Start(req *http.Request) (*http.Request, func(*http.Response, error))
+}
+
+// WithTracer option for remote reader traces every request with given tracer as the outermost middleware
+// A nil tracer traces nothing
+func WithTracer(tracer Tracer) Option {
+	return func(r *Reader) {
+		if tracer == nil {
+			return
+		}
+		r.middlewares = append([]Middleware{tracing(tracer)}, r.middlewares...)
+	}
+}
+
+// tracing is the middleware starting and ending spans with given tracer
+func tracing(tracer Tracer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req, end := tracer.Start(req)
+			resp, err := next.RoundTrip(req)
+			end(resp, err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// BytesFromMirrors reads bytes from the first of given urls which succeeds, trying them in order
+// with configured reader. If all fail, returned error joins the errors of every url
+func (r *Reader) BytesFromMirrors(urls []string) ([]byte, error) {
+	return r.BytesFromMirrorsWithContext(context.Background(), urls)
+}
+
+// BytesFromMirrorsWithContext reads bytes from the first of given urls which succeeds using given context
+func (r *Reader) BytesFromMirrorsWithContext(ctx context.Context, urls []string) ([]byte, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no mirror to read from")
+	}
+	errs := make([]error, 0, len(urls))
+	for _, url := range urls {
+		b, err := r.BytesWithContext(ctx, url)
+		if err == nil {
+			return b, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", redactURL(url), err))
+	}
+	return nil, fmt.Errorf("can't read from any mirror: %w", errors.Join(errs...))
+}
@@ -0,0 +1,132 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// JSONPages reads json pages starting from given url with configured reader calling the handler for every page
+// Pages are followed through the rel="next" link of the Link header until there is none
+// Stops at the first error returned by the handler and returns it
+func (r *Reader) JSONPages(url string, handler func(json.RawMessage) error) error {
+	return r.JSONPagesWithContext(context.Background(), url, handler)
+}
+
+// JSONPagesWithContext reads json pages starting from given url with configured reader using given context
+// calling the handler for every page
+func (r *Reader) JSONPagesWithContext(ctx context.Context, url string, handler func(json.RawMessage) error) error {
+	for url != "" {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		page, resp, err := r.jsonPage(ctx, url)
+		if err != nil {
+			return err
+		}
+		if err = handler(page); err != nil {
+			return err
+		}
+		url = ""
+		if next := nextLink(resp.Header); next != "" {
+			u, err := resp.Request.URL.Parse(next)
+			if err != nil {
+				return wrapError(err, "can't parse next link")
+			}
+			url = u.String()
+		}
+	}
+	return nil
+}
+
+// jsonPage reads a json page from given url
+func (r *Reader) jsonPage(ctx context.Context, url string) (json.RawMessage, *http.Response, error) {
+	r = r.forURL(url)
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	b, err := r.readBody(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !json.Valid(b) {
+		return nil, nil, fmt.Errorf("can't decode json page %q", redactURL(url))
+	}
+	return json.RawMessage(b), resp, nil
+}
+
+// nextLink returns the url of rel="next" link of RFC 5988 Link headers, empty if there is none
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for value != "" {
+			start := strings.IndexByte(value, '<')
+			end := strings.IndexByte(value, '>')
+			if start < 0 || end < start {
+				break
+			}
+			link := value[start+1 : end]
+			value = value[end+1:]
+			params := value
+			if i := strings.IndexByte(value, ','); i >= 0 {
+				params, value = value[:i], value[i+1:]
+			} else {
+				value = ""
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, rel, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(rel, `"`)) {
+					if strings.EqualFold(r, "next") {
+						return link
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Paginate reads json pages starting from given url with configured reader calling the handler for every page
+// The url of the following page is extracted from each page by next, which returns empty to stop
+// Relative urls returned by next are resolved against the url of the page
+func (r *Reader) Paginate(startURL string, next func(page json.RawMessage) (string, error),
+	handler func(json.RawMessage) error) error {
+	return r.PaginateWithContext(context.Background(), startURL, next, handler)
+}
+
+// PaginateWithContext reads json pages starting from given url with configured reader using given context
+// calling the handler for every page and following the urls extracted by next
+func (r *Reader) PaginateWithContext(ctx context.Context, startURL string,
+	next func(page json.RawMessage) (string, error), handler func(json.RawMessage) error) error {
+	for url := startURL; url != ""; {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		page, resp, err := r.jsonPage(ctx, url)
+		if err != nil {
+			return err
+		}
+		if err = handler(page); err != nil {
+			return err
+		}
+		nextURL, err := next(page)
+		if err != nil {
+			return err
+		}
+		url = ""
+		if nextURL != "" {
+			u, err := resp.Request.URL.Parse(nextURL)
+			if err != nil {
+				return wrapError(err, "can't parse next url")
+			}
+			url = u.String()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package remote
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// pooledBodyLimit is the maximum Content-Length of a body read into a pooled buffer
+// so buffers kept by the pool stay small
+const pooledBodyLimit = 64 << 10
+
+// pooledBufferLimit is the maximum capacity of a buffer put back into the pool
+// Buffers grown further, e.g. by a decompressed body larger than its Content-Length, are dropped
+const pooledBufferLimit = 2 * pooledBodyLimit
+
+// bufferPool holds buffers reused to read bodies of known size
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// readPooled reads the whole body of given expected size into a pooled buffer and returns a copy of it
+func readPooled(body io.Reader, size int64) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= pooledBufferLimit {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	// room for the final read which hits the end of the body
+	buf.Grow(int(size) + bytes.MinRead)
+	if _, err := buf.ReadFrom(body); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadPooledDropsGrownBuffers(t *testing.T) {
+	// a decompressed body can be much larger than its Content-Length
+	b, err := readPooled(bytes.NewReader(make([]byte, 8<<20)), 10)
+	if err != nil || len(b) != 8<<20 {
+		t.Fatalf("readPooled() = %d bytes, %v", len(b), err)
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+	if buf.Cap() > pooledBufferLimit {
+		t.Errorf("pooled buffer has capacity of %d bytes, limit is %d", buf.Cap(), pooledBufferLimit)
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	sizes := []struct {
+		name string
+		size int
+	}{{"1KB", 1 << 10}, {"32KB", 32 << 10}, {"1MB", 1 << 20}}
+	for _, s := range sizes {
+		body := bytes.Repeat([]byte("x"), s.size)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write(body)
+		}))
+		reader := NewReader()
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(s.size))
+			for i := 0; i < b.N; i++ {
+				if _, err := reader.Bytes(srv.URL); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		srv.Close()
+	}
+}
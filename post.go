@@ -0,0 +1,94 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Post sends given body with the content type to the url with configured reader
+func (r *Reader) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	return r.PostWithContext(context.Background(), url, contentType, body)
+}
+
+// PostWithContext sends given body with the content type to the url with configured reader using given context
+func (r *Reader) PostWithContext(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	var header http.Header
+	if contentType != "" {
+		header = http.Header{"Content-Type": {contentType}}
+	}
+	return r.do(ctx, http.MethodPost, url, header, body)
+}
+
+// PostJSON encodes payload as json and posts it to the url with configured reader
+func (r *Reader) PostJSON(url string, payload interface{}) (*http.Response, error) {
+	return r.PostJSONWithContext(context.Background(), url, payload)
+}
+
+// PostJSONWithContext encodes payload as json and posts it to the url with configured reader using given context
+func (r *Reader) PostJSONWithContext(ctx context.Context, url string, payload interface{}) (*http.Response, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, wrapError(err, "can't encode json")
+	}
+	return r.PostWithContext(ctx, url, "application/json", bytes.NewReader(b))
+}
+
+// PostForm encodes values as a form and posts them to the url with configured reader
+func (r *Reader) PostForm(url string, values url.Values) (*http.Response, error) {
+	return r.PostFormWithContext(context.Background(), url, values)
+}
+
+// PostFormWithContext encodes values as a form and posts them to the url with configured reader using given context
+func (r *Reader) PostFormWithContext(ctx context.Context, url string, values url.Values) (*http.Response, error) {
+	return r.PostWithContext(ctx, url, "application/x-www-form-urlencoded", strings.NewReader(values.Encode()))
+}
+
+// PostFormJSON encodes values as a form, posts them to the url with configured reader
+// and decodes json body of the response into the destination
+// Status codes not accepted as a success result in HTTPError like JSON
+func (r *Reader) PostFormJSON(url string, values url.Values, dest interface{}) error {
+	return r.PostFormJSONWithContext(context.Background(), url, values, dest)
+}
+
+// PostFormJSONWithContext encodes values as a form, posts them to the url with configured reader using given context
+// and decodes json body of the response into the destination
+func (r *Reader) PostFormJSONWithContext(ctx context.Context, url string, values url.Values, dest interface{}) error {
+	resp, err := r.PostFormWithContext(ctx, url, values)
+	if err != nil {
+		return err
+	}
+	return r.decodeJSONResponse(resp, url, dest)
+}
+
+// PostJSONInto encodes payload as json, posts it to the url with configured reader
+// and decodes json body of the response into the destination
+// Status codes not accepted as a success result in HTTPError, an empty body leaves the destination untouched
+func (r *Reader) PostJSONInto(url string, payload, dest interface{}) error {
+	return r.PostJSONIntoWithContext(context.Background(), url, payload, dest)
+}
+
+// PostJSONIntoWithContext encodes payload as json, posts it to the url with configured reader using given context
+// and decodes json body of the response into the destination
+func (r *Reader) PostJSONIntoWithContext(ctx context.Context, url string, payload, dest interface{}) error {
+	resp, err := r.PostJSONWithContext(ctx, url, payload)
+	if err != nil {
+		return err
+	}
+	return r.decodeJSONResponse(resp, url, dest)
+}
+
+// decodeJSONResponse decodes json body of the response into the destination and closes it
+// failing with HTTPError unless the status is a success
+func (r *Reader) decodeJSONResponse(resp *http.Response, url string, dest interface{}) error {
+	r = r.forURL(url)
+	defer resp.Body.Close()
+	if !r.isSuccess(resp.StatusCode) {
+		return r.statusError(resp, url)
+	}
+	return r.decodeBody(resp, dest, DecodeAsJSON)
+}
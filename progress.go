@@ -0,0 +1,43 @@
+package remote
+
+import (
+	"io"
+	"net/http"
+)
+
+// progressInterval is the number of bytes read between two progress reports
+const progressInterval = 32 << 10
+
+// OnProgress option for remote reader calls given function while Bytes and DownloadTo read a body
+// with the number of bytes downloaded so far and the Content-Length of the response or -1 if unknown
+// It is called every few kilobytes and once more at the end of the body
+func OnProgress(progress func(downloaded, total int64)) Option {
+	return func(r *Reader) { r.progress = progress }
+}
+
+// trackProgress wraps body of the response to report the progress of reading it if configured
+func (r *Reader) trackProgress(resp *http.Response) {
+	if r.progress == nil {
+		return
+	}
+	resp.Body = &progressReader{ReadCloser: resp.Body, total: resp.ContentLength, progress: r.progress}
+}
+
+// progressReader counts the bytes read and reports them every progressInterval bytes and at the end
+type progressReader struct {
+	io.ReadCloser
+	read     int64
+	reported int64
+	total    int64
+	progress func(downloaded, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	p.read += int64(n)
+	if p.read-p.reported >= progressInterval || (err == io.EOF && p.read > p.reported) {
+		p.reported = p.read
+		p.progress(p.read, p.total)
+	}
+	return n, err
+}
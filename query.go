@@ -0,0 +1,63 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Query option for remote reader adds given query parameters to every request
+// Parameters already present in the requested url are kept as they are
+func Query(params map[string]string) Option {
+	return func(r *Reader) {
+		if r.query == nil {
+			r.query = url.Values{}
+		}
+		for key, value := range params {
+			r.query.Set(key, value)
+		}
+	}
+}
+
+// ReadWithQuery returns response from given url with the params merged into its query
+// Params replace values of the same keys already present in the url
+func (r *Reader) ReadWithQuery(url string, params url.Values) (*http.Response, error) {
+	return r.ReadWithQueryWithContext(context.Background(), url, params)
+}
+
+// ReadWithQueryWithContext returns response from given url with the params merged into its query using given context
+func (r *Reader) ReadWithQueryWithContext(ctx context.Context, url string, params url.Values) (*http.Response, error) {
+	u, err := withQuery(url, params)
+	if err != nil {
+		return nil, err
+	}
+	return r.ReadWithContext(ctx, u)
+}
+
+// withQuery merges params into the query of given url replacing values of the same keys
+func withQuery(rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", wrapError(err, "can't parse url")
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = append([]string(nil), values...)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// addQuery adds parameters of Query option missing in the url of the request
+func (r *Reader) addQuery(req *http.Request) {
+	if len(r.query) == 0 {
+		return
+	}
+	query := req.URL.Query()
+	for key, values := range r.query {
+		if _, ok := query[key]; !ok {
+			query[key] = values
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+}
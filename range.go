@@ -0,0 +1,69 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrRangeIgnored is returned along with the response by ReadRange
+// when the server ignores the range and responds with the whole content
+var ErrRangeIgnored = errors.New("server ignored the range of the request")
+
+// ReadRange returns response for bytes from start to end inclusive of given url with configured reader
+// Negative end reads until the end of the content
+// If the server ignores the range the 200 response is returned with ErrRangeIgnored,
+// the body holds the whole content then and still needs to be closed
+func (r *Reader) ReadRange(url string, start, end int64) (*http.Response, error) {
+	return r.ReadRangeWithContext(context.Background(), url, start, end)
+}
+
+// ReadRangeWithContext returns response for bytes from start to end inclusive of given url
+// with configured reader using given context
+func (r *Reader) ReadRangeWithContext(ctx context.Context, url string, start, end int64) (*http.Response, error) {
+	r = r.forURL(url)
+	byteRange := fmt.Sprintf("bytes=%d-", start)
+	if end >= 0 {
+		byteRange += strconv.FormatInt(end, 10)
+	}
+	resp, err := r.do(ctx, http.MethodGet, url, http.Header{"Range": {byteRange}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp, nil
+	case http.StatusOK:
+		return resp, ErrRangeIgnored
+	default:
+		defer resp.Body.Close()
+		return nil, r.statusError(resp, url)
+	}
+}
+
+// ParseContentRange parses a Content-Range header value like "bytes 0-99/1000"
+// Size is -1 if unknown to the server
+func ParseContentRange(value string) (start, end, size int64, err error) {
+	spec := strings.TrimPrefix(strings.TrimSpace(value), "bytes ")
+	slash := strings.IndexByte(spec, '/')
+	dash := strings.IndexByte(spec, '-')
+	if slash < 0 || dash < 0 || dash > slash {
+		return 0, 0, 0, fmt.Errorf("can't parse content range %q", value)
+	}
+	if start, err = strconv.ParseInt(spec[:dash], 10, 64); err != nil {
+		return 0, 0, 0, wrapErrorf(err, "can't parse content range %q", value)
+	}
+	if end, err = strconv.ParseInt(spec[dash+1:slash], 10, 64); err != nil {
+		return 0, 0, 0, wrapErrorf(err, "can't parse content range %q", value)
+	}
+	if spec[slash+1:] == "*" {
+		return start, end, -1, nil
+	}
+	if size, err = strconv.ParseInt(spec[slash+1:], 10, 64); err != nil {
+		return 0, 0, 0, wrapErrorf(err, "can't parse content range %q", value)
+	}
+	return start, end, size, nil
+}
@@ -0,0 +1,65 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit option for remote reader limits requests to rps per second allowing bursts of given size
+// Every attempt waits for its turn, shared by all goroutines using the reader
+func RateLimit(rps int, burst int) Option {
+	return func(r *Reader) {
+		if rps <= 0 {
+			r.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		r.limiter = &limiter{
+			interval: time.Second / time.Duration(rps),
+			burst:    float64(burst),
+			tokens:   float64(burst),
+			last:     time.Now(),
+		}
+	}
+}
+
+// limiter is a token bucket in the manner of golang.org/x/time/rate
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// wait takes a token waiting until it's available unless the context is done before
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// a negative balance is the queue of waiting callers
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens * float64(l.interval))
+	}
+	l.mu.Unlock()
+	if err := sleep(ctx, delay); err != nil {
+		// give back the token which won't be used
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
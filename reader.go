@@ -1,122 +1,947 @@
 package remote
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
+// drainLimit is the maximum number of bytes read from an unused body before closing it
+const drainLimit = 64 << 10
+
+// rewindLimit is the maximum size of a request body without GetBody which is buffered to be sent again by retries
+const rewindLimit = 1 << 20
+
+// ErrBodyTooLarge is returned when body of response exceeds the MaxBytes option
+var ErrBodyTooLarge = errors.New("body of response is too large")
+
+// ErrEmptyBody is returned by JSONStrict when body of response is empty
+var ErrEmptyBody = errors.New("body of response is empty")
+
 // Option is an option to set on remote reader
+// Options must only be applied via NewReader, applying one to a reader in use is a data race
 type Option func(*Reader)
 
 // Reader is a client to read remote bytes or json
-// Should be created via NewRemoteReader to configure
+// Should be created via NewReader to configure
 // Defaults 1 retry and 5 seconds timeout
+// Reader is safe for concurrent use by multiple goroutines once created, its shared state
+// like cache, rate limiter and connection pool is synchronized. Functions given via options,
+// e.g. BearerTokenFunc, OnRetry or a Logger, may be called concurrently and must be safe for it
 type Reader struct {
-	retry         uint
-	timeout       time.Duration
-	skipTLSVerify bool
-	userAgent     string
+	retry                 uint
+	retryNonIdempotent    bool
+	retryMode             RetryMode
+	timeout               time.Duration
+	overallTimeout        time.Duration
+	ctx                   context.Context
+	skipTLSVerify         bool
+	certificates          []tls.Certificate
+	rootCAs               *x509.CertPool
+	minTLSVersion         uint16
+	userAgent             string
+	userAgentFunc         func() string
+	header                http.Header
+	query                 url.Values
+	basicAuth             bool
+	username              string
+	password              string
+	tokenFunc             func() (string, error)
+	sign                  func(*http.Request) error
+	backoffBase           time.Duration
+	backoffMax            time.Duration
+	jitter                float64
+	random                *lockedRand
+	retryBudget           time.Duration
+	retryStatus           map[int]bool
+	successStatus         map[int]bool
+	acceptAny2xx          bool
+	onRetry               func(attempt uint, err error)
+	retryIf               func(*http.Response, error) bool
+	retryIfOnly           bool
+	validate              func(*http.Response, []byte) error
+	logger                Logger
+	metrics               MetricsCollector
+	debug                 *debugWriter
+	redactHeaders         []string
+	onTrace               func(*RequestTrace)
+	flights               *flightGroup
+	cacheTTL              time.Duration
+	cacheSize             int
+	cache                 *memoryCache
+	diskCache             *diskCache
+	limiter               *limiter
+	slots                 semaphore
+	breaker               *breaker
+	maxRedirects          int
+	authDomains           []string
+	jar                   http.CookieJar
+	balancer              Balancer
+	baseURL               *url.URL
+	maxBytes              int64
+	errorBodyLimit        int
+	uploadProgress        func(sent, total int64)
+	progress              func(downloaded, total int64)
+	csvDelimiter          rune
+	acceptEncoding        string
+	accept                string
+	proxy                 func(*http.Request) (*url.URL, error)
+	dialTimeout           time.Duration
+	responseHeaderTimeout time.Duration
+	maxIdleConns          int
+	maxIdleConnsPerHost   int
+	idleConnTimeout       time.Duration
+	disableKeepAlives     bool
+	unixSocket            string
+	forceHTTP1            bool
+	h2c                   bool
+	resolver              *net.Resolver
+	hostOverrides         map[string]string
+	hostHeader            string
+	localAddr             *net.TCPAddr
+	roundTripper          http.RoundTripper
+	middlewares           []Middleware
+	hostOptions           map[string][]Option
+	hosts                 map[string]*Reader
+	err                   error
+	client                *http.Client
 }
 
 // NewReader creates a new remote reader with defaults
 func NewReader(options ...Option) *Reader {
+	r := newReader(options)
+	for host, hostOptions := range r.hostOptions {
+		// options of the host are applied on top of the ones of the reader
+		all := append(append([]Option(nil), options...), hostOptions...)
+		if r.hosts == nil {
+			r.hosts = make(map[string]*Reader, len(r.hostOptions))
+		}
+		r.hosts[host] = newReader(all)
+	}
+	return r
+}
+
+// newReader creates a remote reader with defaults and given options ignoring HostConfig
+func newReader(options []Option) *Reader {
 	r := &Reader{
-		retry:     1,
-		timeout:   5 * time.Second,
-		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.81 Safari/537.36", // nolint: lll
+		retry:          1,
+		timeout:        5 * time.Second,
+		header:         http.Header{},
+		maxRedirects:   defaultMaxRedirects,
+		jitter:         defaultJitter,
+		errorBodyLimit: defaultErrorBodyLimit,
+		userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.81 Safari/537.36", // nolint: lll
 	}
 	for _, option := range options {
 		option(r)
 	}
+	if r.cacheTTL > 0 {
+		r.cache = newMemoryCache(r.cacheTTL, r.cacheSize)
+	}
+	attemptTimeout := r.timeout
+	if r.retryMode == RetryWithinDeadline && r.timeout > 0 {
+		if r.overallTimeout == 0 || r.timeout < r.overallTimeout {
+			r.overallTimeout = r.timeout
+		}
+		attemptTimeout = 0
+	}
+	if r.client == nil {
+		r.client = &http.Client{
+			Timeout:       attemptTimeout,
+			Transport:     r.newRoundTripper(),
+			CheckRedirect: r.checkRedirect,
+			Jar:           r.jar,
+		}
+	} else if len(r.middlewares) > 0 || r.debug != nil {
+		// copy the given client not to alter it for its other users
+		client := *r.client
+		client.Transport = r.wrap(client.Transport)
+		r.client = &client
+	}
 	return r
 }
 
 // Retry option for remote reader
+// Only GET and HEAD requests are retried unless RetryNonIdempotent is given
 func Retry(retry uint) Option { return func(r *Reader) { r.retry = retry } }
 
+// RetryMode tells how Timeout applies to a call with retries
+type RetryMode int
+
+const (
+	// RetryPerAttempt applies Timeout to every attempt so a call with Retry(3) can take three times as long
+	// plus the delays between attempts. This is the default
+	RetryPerAttempt RetryMode = iota
+	// RetryWithinDeadline applies Timeout to the whole call like OverallTimeout,
+	// attempts are made as long as the deadline allows and the last one can use all the time left
+	RetryWithinDeadline
+)
+
+// WithRetryMode option for remote reader sets how Timeout applies to a call with retries
+// Defaults to RetryPerAttempt
+func WithRetryMode(mode RetryMode) Option { return func(r *Reader) { r.retryMode = mode } }
+
+// RetryNonIdempotent option for remote reader retries requests of any method like POST
+// Use it only if the endpoints are safe to call more than once
+func RetryNonIdempotent() Option { return func(r *Reader) { r.retryNonIdempotent = true } }
+
+// RetryOnStatus option for remote reader retries responses with given status codes as well as timeouts
+// Retries on 429, 502, 503 and 504 if no code is given
+// Retry-After header of a retried response is honored
+func RetryOnStatus(codes ...int) Option {
+	if len(codes) == 0 {
+		codes = []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+	return func(r *Reader) {
+		r.retryStatus = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			r.retryStatus[code] = true
+		}
+	}
+}
+
+// SuccessStatus option for remote reader sets the status codes accepted by Bytes, JSON and alike
+// Defaults to only 200 OK
+func SuccessStatus(codes ...int) Option {
+	return func(r *Reader) {
+		r.acceptAny2xx = false
+		r.successStatus = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			r.successStatus[code] = true
+		}
+	}
+}
+
+// AcceptAny2xx option for remote reader accepts any 2xx status code as a success in Bytes, JSON and alike
+// e.g. 201 Created or 202 Accepted
+func AcceptAny2xx() Option {
+	return func(r *Reader) {
+		r.acceptAny2xx = true
+		r.successStatus = nil
+	}
+}
+
+// StrictOK option for remote reader accepts only 200 OK as a success in Bytes, JSON and alike
+// This is the default, it undoes AcceptAny2xx and SuccessStatus
+func StrictOK() Option {
+	return func(r *Reader) {
+		r.acceptAny2xx = false
+		r.successStatus = nil
+	}
+}
+
+// MaxBytes option for remote reader limits size of the body read by Bytes and JSON
+// Exceeding the limit results in ErrBodyTooLarge, defaults to 0 which means unlimited
+func MaxBytes(n int64) Option { return func(r *Reader) { r.maxBytes = n } }
+
+// RetryIf option for remote reader decides whether to retry an attempt with given predicate
+// instead of the built-in timeout and RetryOnStatus checks
+// Predicate gets the response before its body is read, or the error if the attempt failed
+func RetryIf(retryIf func(resp *http.Response, err error) bool) Option {
+	return func(r *Reader) {
+		r.retryIf = retryIf
+		r.retryIfOnly = true
+	}
+}
+
+// RetryAlsoIf option for remote reader retries an attempt if given predicate holds
+// on top of the built-in timeout and RetryOnStatus checks
+func RetryAlsoIf(retryIf func(resp *http.Response, err error) bool) Option {
+	return func(r *Reader) {
+		r.retryIf = retryIf
+		r.retryIfOnly = false
+	}
+}
+
+// OnRetry option for remote reader calls given function every time a failed attempt is retried
+// with the number of the failed attempt starting from 1 and its error,
+// which is an HTTPError if the attempt is retried due to its status
+func OnRetry(onRetry func(attempt uint, err error)) Option {
+	return func(r *Reader) { r.onRetry = onRetry }
+}
+
 // Timeout option for remote reader
+// Applies to every attempt unless WithRetryMode(RetryWithinDeadline) makes it apply to the whole call
 func Timeout(timeout time.Duration) Option {
 	return func(r *Reader) {
 		r.timeout = timeout
 	}
 }
 
+// OverallTimeout option for remote reader limits the time of a whole call including all attempts,
+// the delays between them and reading the body, while Timeout applies to every attempt
+// Exceeding it results in an error matching context.DeadlineExceeded and ErrTimeout
+func OverallTimeout(timeout time.Duration) Option {
+	return func(r *Reader) { r.overallTimeout = timeout }
+}
+
 // SkipTLSVerify option for remote reader to skip TLS Certificate verification
 func SkipTLSVerify() Option { return func(r *Reader) { r.skipTLSVerify = true } }
 
 // UserAgent option for remote reader sets the user agent header string for the request
 func UserAgent(userAgent string) Option { return func(r *Reader) { r.userAgent = userAgent } }
 
-// Read returns response from given url with configured reader
-func (r *Reader) Read(url string) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-	var i uint
-	for i = 0; i < r.retry; i++ {
-		if resp, err = r.get(url); err == nil || !isTimeoutErr(err) {
-			return resp, errors.Wrap(err, "can't get url")
+// UserAgentFunc option for remote reader sets the user agent header string returned by given function
+// It is called for every request and takes precedence over UserAgent
+func UserAgentFunc(userAgent func() string) Option {
+	return func(r *Reader) { r.userAgentFunc = userAgent }
+}
+
+// WithClient option for remote reader uses given client for all requests
+// Timeout and transport options like SkipTLSVerify are ignored in favor of the client's own configuration
+// A nil client falls back to the default one built by the reader
+func WithClient(client *http.Client) Option { return func(r *Reader) { r.client = client } }
+
+// Header option for remote reader sets a header for every request
+// Setting the same key again replaces the previous value
+func Header(key, value string) Option { return func(r *Reader) { r.header.Set(key, value) } }
+
+// AddHeader option for remote reader adds a value to a header for every request
+// Unlike Header it appends to any existing values to build multi-value headers
+func AddHeader(key, value string) Option { return func(r *Reader) { r.header.Add(key, value) } }
+
+// Headers option for remote reader sets given headers for every request
+func Headers(headers map[string]string) Option {
+	return func(r *Reader) {
+		for key, value := range headers {
+			r.header.Set(key, value)
 		}
 	}
-	return resp, errors.Wrap(err, "can't read url")
+}
+
+// BasicAuth option for remote reader authenticates every request with given credentials
+// It takes precedence over an Authorization header set via Header option
+func BasicAuth(username, password string) Option {
+	return func(r *Reader) {
+		r.basicAuth = true
+		r.username = username
+		r.password = password
+		r.tokenFunc = nil
+	}
+}
+
+// BearerToken option for remote reader authenticates every request with given bearer token
+func BearerToken(token string) Option {
+	return BearerTokenFunc(func() (string, error) { return token, nil })
+}
+
+// BearerTokenFunc option for remote reader authenticates every request with a bearer token
+// returned by given function, which is called per request so expiring tokens can be refreshed
+// Request is not sent if the function fails
+func BearerTokenFunc(tokenFunc func() (string, error)) Option {
+	return func(r *Reader) {
+		r.tokenFunc = tokenFunc
+		r.basicAuth = false
+	}
+}
+
+// SignRequest option for remote reader calls given function with every request right before sending it
+// so it can be signed after all other options are applied, e.g. for AWS SigV4 or HMAC authentication
+// Request is not sent if the function fails
+func SignRequest(sign func(*http.Request) error) Option { return func(r *Reader) { r.sign = sign } }
+
+// Read returns response from given url with configured reader
+func (r *Reader) Read(url string) (*http.Response, error) {
+	return r.ReadWithContext(context.Background(), url)
+}
+
+// ReadWithContext returns response from given url with configured reader
+// Request is cancelled and retries stop as soon as the context is done
+func (r *Reader) ReadWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return r.do(ctx, http.MethodGet, url, nil, nil)
+}
+
+// Do sends given request with configured reader applying its retries, headers and other options
+// Headers of the request take precedence over the ones configured for the reader
+// Context of the request is used for all attempts and the body is rewound via GetBody for retries
+func (r *Reader) Do(req *http.Request) (*http.Response, error) {
+	resp, _, err := r.doRequest(req, req.URL.String())
+	return resp, err
 }
 
 // Bytes reads bytes from given url with configured reader
 func (r *Reader) Bytes(url string) ([]byte, error) {
-	resp, err := r.Read(url)
+	return r.BytesWithContext(context.Background(), url)
+}
+
+// BytesWithContext reads bytes from given url with configured reader using given context
+func (r *Reader) BytesWithContext(ctx context.Context, url string) ([]byte, error) {
+	b, _, err := r.bytesWithResponse(ctx, url)
+	return b, err
+}
+
+// BytesWithTimeout reads bytes from given url with configured reader within given timeout
+// Timeout of the reader still applies to every attempt so the smaller one wins
+func (r *Reader) BytesWithTimeout(url string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.BytesWithContext(ctx, url)
+}
+
+// String reads body of given url as a string with configured reader
+// Status codes not accepted as a success result in HTTPError like Bytes
+func (r *Reader) String(url string) (string, error) {
+	return r.StringWithContext(context.Background(), url)
+}
+
+// StringWithContext reads body of given url as a string with configured reader using given context
+func (r *Reader) StringWithContext(ctx context.Context, url string) (string, error) {
+	b, err := r.BytesWithContext(ctx, url)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	return string(b), nil
+}
+
+// BytesStatus reads bytes from given url with configured reader and returns them along with the status code
+// for any completed response, so errors are only returned for failed requests and not for statuses
+func (r *Reader) BytesStatus(url string) ([]byte, int, error) {
+	return r.BytesStatusWithContext(context.Background(), url)
+}
+
+// BytesStatusWithContext reads bytes from given url with configured reader using given context
+// and returns them along with the status code for any completed response
+func (r *Reader) BytesStatusWithContext(ctx context.Context, url string) ([]byte, int, error) {
+	r = r.forURL(url)
+	resp, err := r.ReadWithContext(ctx, url)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("Got %q: can't read given url %q", resp.Status, url)
+	b, err := r.readBody(resp)
+	return b, resp.StatusCode, err
+}
+
+// BytesWithResponse reads bytes from given url with configured reader
+// and returns them along with the response to inspect its status and headers
+// Body of the returned response is already read and closed,
+// its Request.URL is the final url after redirects
+func (r *Reader) BytesWithResponse(url string) ([]byte, *http.Response, error) {
+	return r.bytesWithResponse(context.Background(), url)
+}
+
+func (r *Reader) bytesWithResponse(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	r = r.forURL(url)
+	if r.cache == nil {
+		return r.storedBytes(ctx, url)
+	}
+	if b, resp, ok := r.cache.get(url); ok {
+		return b, resp, nil
+	}
+	b, resp, err := r.storedBytes(ctx, url)
+	if err == nil {
+		r.cache.set(url, b, resp)
+	}
+	return b, resp, err
+}
+
+// storedBytes reads the whole body of given url through the disk cache if configured
+func (r *Reader) storedBytes(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	if r.diskCache != nil {
+		return r.diskBytes(ctx, url)
+	}
+	return r.sharedBytes(ctx, url)
+}
+
+// sharedBytes reads the whole body of given url sharing the request with concurrent calls if configured
+func (r *Reader) sharedBytes(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	if r.flights != nil {
+		return r.flights.do(ctx, url, func() ([]byte, *http.Response, error) { return r.fetchBytes(ctx, url) })
+	}
+	return r.fetchBytes(ctx, url)
+}
+
+// fetchBytes reads the whole body of given url validating it if configured
+func (r *Reader) fetchBytes(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	if r.validate != nil {
+		return r.fetchValid(ctx, url)
 	}
-	b, err := ioutil.ReadAll(resp.Body)
-	return b, errors.Wrap(err, "can't read body of response")
+	return r.readBytes(ctx, url)
+}
+
+// readBytes reads the whole body of given url
+func (r *Reader) readBytes(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return nil, resp, err
+	}
+	defer resp.Body.Close()
+	r.trackProgress(resp)
+	b, err := r.readBody(resp)
+	return b, resp, err
+}
+
+// readBody reads the whole body of the response
+func (r *Reader) readBody(resp *http.Response) ([]byte, error) {
+	body, err := r.body(resp)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	if resp.ContentLength > 0 && resp.ContentLength <= pooledBodyLimit {
+		b, err = readPooled(body, resp.ContentLength)
+	} else {
+		b, err = ioutil.ReadAll(body)
+	}
+	if err == ErrBodyTooLarge {
+		return nil, err
+	}
+	return b, wrapError(err, "can't read body of response")
 }
 
 // JSON reads bytes from given url with configured reader and decodes body into the destination
 func (r *Reader) JSON(url string, dest interface{}) error {
-	resp, err := r.Read(url)
+	return r.JSONWithContext(context.Background(), url, dest)
+}
+
+// JSONWithContext reads bytes from given url with configured reader using given context
+// and decodes body into the destination
+func (r *Reader) JSONWithContext(ctx context.Context, url string, dest interface{}) error {
+	return r.decode(ctx, url, dest, DecodeAsJSON)
+}
+
+// JSONWithTimeout reads bytes from given url with configured reader within given timeout
+// and decodes body into the destination. Timeout of the reader still applies to every attempt
+func (r *Reader) JSONWithTimeout(url string, dest interface{}, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.JSONWithContext(ctx, url, dest)
+}
+
+// JSONStrict reads bytes from given url with configured reader and decodes body into the destination
+// Unlike JSON an empty body results in ErrEmptyBody instead of leaving the destination untouched
+func (r *Reader) JSONStrict(url string, dest interface{}) error {
+	return r.JSONStrictWithContext(context.Background(), url, dest)
+}
+
+// JSONStrictWithContext reads bytes from given url with configured reader using given context
+// and decodes body into the destination failing with ErrEmptyBody if body is empty
+func (r *Reader) JSONStrictWithContext(ctx context.Context, url string, dest interface{}) error {
+	return r.decode(ctx, url, dest, func(body io.Reader, dest interface{}) error {
+		err := json.NewDecoder(body).Decode(dest)
+		if err == io.EOF {
+			return ErrEmptyBody
+		}
+		return wrapError(err, "can't decode json")
+	})
+}
+
+// JSONMap reads bytes from given url with configured reader and decodes body into a new map
+// Empty body results in a nil map like JSON leaves its destination untouched
+func (r *Reader) JSONMap(url string) (map[string]interface{}, error) {
+	return r.JSONMapWithContext(context.Background(), url)
+}
+
+// JSONMapWithContext reads bytes from given url with configured reader using given context
+// and decodes body into a new map
+func (r *Reader) JSONMapWithContext(ctx context.Context, url string) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := r.JSONWithContext(ctx, url, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// JSONArray reads bytes from given url with configured reader and decodes body into a new slice
+// Empty body results in a nil slice like JSON leaves its destination untouched
+func (r *Reader) JSONArray(url string) ([]interface{}, error) {
+	return r.JSONArrayWithContext(context.Background(), url)
+}
+
+// JSONArrayWithContext reads bytes from given url with configured reader using given context
+// and decodes body into a new slice
+func (r *Reader) JSONArrayWithContext(ctx context.Context, url string) ([]interface{}, error) {
+	var a []interface{}
+	if err := r.JSONWithContext(ctx, url, &a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// decoder decodes a body into the destination
+type decoder func(io.Reader, interface{}) error
+
+// decode reads given url and decodes body into the destination with given decoder
+func (r *Reader) decode(ctx context.Context, url string, dest interface{}, dec decoder) error {
+	return r.decodeWith(ctx, url, dest, func(*http.Response) (decoder, error) { return dec, nil })
+}
+
+// decodeWith reads given url and decodes body into the destination with the decoder chosen for the response
+func (r *Reader) decodeWith(ctx context.Context, url string, dest interface{},
+	choose func(*http.Response) (decoder, error)) error {
+	r = r.forURL(url)
+	if r.flights != nil || r.cache != nil || r.diskCache != nil || r.validate != nil {
+		// decode the shared or cached body instead of streaming the own one
+		b, resp, err := r.bytesWithResponse(ctx, url)
+		if err != nil {
+			return err
+		}
+		dec, err := choose(resp)
+		if err != nil {
+			return err
+		}
+		return dec(bytes.NewReader(b), dest)
+	}
+	resp, err := r.readOK(ctx, url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("Got %q: can't read given url %q", resp.Status, url)
+	dec, err := choose(resp)
+	if err != nil {
+		return err
+	}
+	return r.decodeBody(resp, dest, dec)
+}
+
+// decodeBody decodes body of the response into the destination with given decoder
+func (r *Reader) decodeBody(resp *http.Response, dest interface{}, dec decoder) error {
+	body, err := r.body(resp)
+	if err != nil {
+		return err
+	}
+	err = dec(body, dest)
+	if errors.Is(err, ErrBodyTooLarge) {
+		return ErrBodyTooLarge
+	}
+	return err
+}
+
+// readOK reads given url and fails unless the response status is a success
+// Body of the response is closed on failure but the response is still returned if there is one
+func (r *Reader) readOK(ctx context.Context, url string) (*http.Response, error) {
+	r = r.forURL(url)
+	resp, err := r.ReadWithContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if !r.isSuccess(resp.StatusCode) {
+		defer resp.Body.Close()
+		return resp, r.statusError(resp, url)
+	}
+	return resp, nil
+}
+
+// isSuccess checks if given status code is accepted as a success
+func (r *Reader) isSuccess(code int) bool {
+	if r.acceptAny2xx {
+		return code >= 200 && code < 300
+	}
+	if r.successStatus == nil {
+		return code == http.StatusOK
+	}
+	return r.successStatus[code]
+}
+
+// do sends a request with given method, headers and body, retrying on timeouts and configured status codes
+// Given headers are set on top of the ones configured for the reader
+func (r *Reader) do(ctx context.Context, method, url string, header http.Header, body io.Reader) (*http.Response, error) {
+	resp, _, err := r.doWithStats(ctx, method, url, header, body)
+	return resp, err
+}
+
+// doWithStats is do which also reports the attempts made
+func (r *Reader) doWithStats(ctx context.Context, method, url string, header http.Header, body io.Reader) (*http.Response, Stats, error) {
+	if r.err != nil {
+		// configuration error of an option
+		return nil, Stats{}, r.err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, Stats{}, wrapError(err, "can't get url")
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return r.doRequest(req, url)
+}
+
+// doRequest sends given request to given url, which may be relative to the hosts of the reader,
+// retrying on timeouts and configured status codes
+// Every attempt sends a copy of the request with the configuration of the reader applied
+func (r *Reader) doRequest(req *http.Request, url string) (resp *http.Response, stats Stats, err error) {
+	if r.err != nil {
+		// configuration error of an option
+		return nil, stats, r.err
+	}
+	url = r.withBase(url)
+	if hr := r.hostReader(url); hr != nil {
+		return hr.doRequest(req, url)
 	}
-	return DecodeAsJSON(resp.Body, dest)
+	ctx := req.Context()
+	defer func() {
+		if isTimeoutErr(err) {
+			err = &timeoutError{err: err}
+		}
+	}()
+	if r.ctx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = withBase(ctx, r.ctx)
+		defer func() { cancelOnClose(resp, err, cancel) }()
+	}
+	if r.overallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.overallTimeout)
+		// the deadline covers reading the body too so it's canceled once the body is closed
+		defer func() { cancelOnClose(resp, err, cancel) }()
+	}
+	attempts := r.retry
+	balanced := r.isBalanced(url)
+	if balanced && attempts < 2 {
+		// try at least another host
+		attempts = 2
+	}
+	if !r.retryNonIdempotent && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		// a retry could repeat a write
+		attempts = 1
+	}
+	rewindable := true
+	if attempts > 1 {
+		if req, rewindable, err = bufferBody(req); err != nil {
+			return nil, stats, err
+		}
+	}
+	began := time.Now()
+	defer func() {
+		stats.TotalDuration = time.Since(began)
+		if resp != nil {
+			stats.StatusCode = resp.StatusCode
+		}
+	}()
+	var i uint
+	for i = 0; i < attempts; i++ {
+		target := r.resolveURL(url)
+		if err = r.breaker.allow(target); err != nil {
+			if balanced && i+1 < attempts {
+				// another host may be available
+				continue
+			}
+			return nil, stats, err
+		}
+		if err = r.limiter.wait(ctx); err != nil {
+			r.breaker.record(target, nil, err, true)
+			return nil, stats, err
+		}
+		if err = r.slots.acquire(ctx); err != nil {
+			r.breaker.record(target, nil, err, true)
+			return nil, stats, err
+		}
+		var outgoing *http.Request
+		if outgoing, err = r.prepare(ctx, req, target, i); err != nil {
+			// nothing is sent so it's neither an attempt nor a failure of the host
+			r.slots.releaseOnClose(nil, err)
+			r.breaker.record(target, nil, err, true)
+			return nil, stats, wrapError(err, "can't get url")
+		}
+		start := time.Now()
+		attemptCtx, finishTrace := r.startTrace(ctx, target, i+1)
+		resp, err = r.client.Do(outgoing.WithContext(attemptCtx))
+		stats.Attempts++
+		r.slots.releaseOnClose(resp, err)
+		r.breaker.record(target, resp, err, ctx.Err() != nil)
+		finishTrace(err)
+		duration := time.Since(start)
+		r.logAttempt(req.Method, target, i+1, resp, err, duration)
+		r.observe(target, resp, err, duration)
+		if ctx.Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			// return context error as is so it can be checked via errors.Is
+			return nil, stats, ctx.Err()
+		}
+		if !r.shouldRetry(resp, err, balanced) {
+			return resp, stats, wrapError(err, "can't get url")
+		}
+		if i+1 == attempts {
+			break
+		}
+		delay := r.delay(i, resp)
+		if exceedsDeadline(ctx, delay) || r.exceedsBudget(began, delay) {
+			// no point in sleeping past the deadline of the caller or the retry budget
+			break
+		}
+		if !rewindable {
+			// the error captures the beginning of the body before the rest is drained
+			err = r.retryError(resp, err, target)
+			if resp != nil {
+				drain(resp.Body)
+			}
+			return nil, stats, wrapError(err, "can't retry request with a body that can't be rewound")
+		}
+		if r.onRetry != nil {
+			r.onRetry(i+1, r.retryError(resp, err, target))
+		}
+		if resp != nil {
+			drain(resp.Body)
+		}
+		if ctxErr := sleep(ctx, delay); ctxErr != nil {
+			return nil, stats, ctxErr
+		}
+	}
+	return resp, stats, wrapError(err, "can't read url")
 }
 
-func (r *Reader) get(url string) (*http.Response, error) {
-	client := &http.Client{Timeout: r.timeout}
-	if r.skipTLSVerify {
-		client.Transport = &http.Transport{
-			/* #nosec */
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+// shouldRetry checks if an attempt with given result is worth retrying
+// Any failure of a balanced request is, as another host may succeed
+func (r *Reader) shouldRetry(resp *http.Response, err error, balanced bool) bool {
+	if r.retryIf != nil {
+		if r.retryIf(resp, err) {
+			return true
+		}
+		if r.retryIfOnly {
+			return false
 		}
 	}
-	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return balanced || isTimeoutErr(err)
+	}
+	return r.retryStatus[resp.StatusCode]
+}
+
+// bufferBody returns a copy of given request whose body is buffered to be sent again by retries
+// unless it has GetBody already or is larger than rewindLimit, which is reported as not rewindable
+func bufferBody(req *http.Request) (*http.Request, bool, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, true, nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(req.Body, rewindLimit+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, false, wrapError(err, "can't read body of request")
+	}
+	body := req.Body
+	req = req.WithContext(req.Context())
+	if len(b) > rewindLimit {
+		// send what's read along with the rest of the stream without retries
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(b), body), body}
+		return req, false, nil
+	}
+	body.Close()
+	req.ContentLength = int64(len(b))
+	req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(b)), nil }
+	req.Body, _ = req.GetBody()
+	return req, true, nil
+}
+
+// prepare returns the request of a single attempt to given target
+func (r *Reader) prepare(ctx context.Context, template *http.Request, target string, attempt uint) (*http.Request, error) {
+	req := template.Clone(ctx)
+	if target != template.URL.String() {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		req.URL, req.Host = u, ""
+	}
+	if attempt > 0 && template.GetBody != nil {
+		// the body of the previous attempt is already consumed
+		body, err := template.GetBody()
+		if err != nil {
+			return nil, wrapError(err, "can't rewind body of request")
+		}
+		req.Body = body
+	}
+	if r.hostHeader != "" && (req.Host == "" || req.Host == req.URL.Host) {
+		req.Host = r.hostHeader
+	}
+	r.addQuery(req)
+	if req.Header.Get("User-Agent") == "" {
+		if r.userAgentFunc != nil {
+			req.Header.Set("User-Agent", r.userAgentFunc())
+		} else {
+			req.Header.Set("User-Agent", r.userAgent)
+		}
+	}
+	if r.accept != "" && req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", r.accept)
+	}
+	if r.acceptEncoding != "" && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", r.acceptEncoding)
+	}
+	for key, values := range r.header {
+		if _, ok := template.Header[key]; !ok {
+			req.Header[key] = append([]string(nil), values...)
+		}
+	}
+	if r.basicAuth {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	if r.tokenFunc != nil {
+		token, err := r.tokenFunc()
+		if err != nil {
+			return nil, wrapError(err, "can't get bearer token")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if r.sign != nil {
+		if err := r.sign(req); err != nil {
+			return nil, wrapError(err, "can't sign request")
+		}
+	}
+	return req, nil
+}
+
+// body returns the decompressed body of the response limited by MaxBytes option
+func (r *Reader) body(resp *http.Response) (io.Reader, error) {
+	body, err := r.decompress(resp)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", r.userAgent)
-	return client.Do(req)
+	return r.limitBody(body), nil
 }
 
-// isTimeoutErr checks if given error is a timeout
-func isTimeoutErr(err error) bool {
-	if err == nil {
-		return false
+// limitBody wraps the body to fail with ErrBodyTooLarge once MaxBytes is exceeded
+func (r *Reader) limitBody(body io.Reader) io.Reader {
+	if r.maxBytes <= 0 {
+		return body
 	}
-	urlError, ok := err.(*url.Error)
-	return ok && urlError.Timeout()
+	return &limitedReader{r: body, remaining: r.maxBytes + 1}
+}
+
+// limitedReader is an io.LimitReader which fails instead of ending the stream
+// remaining includes one extra byte to tell a body of exact size from a larger one
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	if l.remaining -= int64(n); l.remaining <= 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+// drain reads the rest of the body up to a limit and closes it
+// so the underlying connection can be reused
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(body, drainLimit))
+	body.Close()
 }
 
 // DecodeAsJSON decodes given reader into destination
@@ -126,5 +951,5 @@ func DecodeAsJSON(r io.Reader, dest interface{}) error {
 	if err == io.EOF {
 		return nil
 	}
-	return errors.Wrap(err, "can't decode json")
+	return wrapError(err, "can't decode json")
 }
@@ -0,0 +1,111 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestConcurrentUse hammers a single reader with shared state from many goroutines, run with -race
+func TestConcurrentUse(t *testing.T) {
+	var inFlight, maxInFlight, retries int32
+	var failed sync.Map
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		if _, done := failed.LoadOrStore(req.URL.Path, true); !done && strings.HasPrefix(req.URL.Path, "/flaky/") {
+			// every flaky path fails once to be retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":%q}`, req.URL.Path)
+	}))
+	defer srv.Close()
+	var debug bytes.Buffer
+	reader := NewReader(
+		Cache(time.Minute),
+		Deduplicate(),
+		RateLimit(10000, 100),
+		MaxConcurrent(4),
+		CircuitBreaker(100, time.Second),
+		Retry(3),
+		RetryOnStatus(http.StatusServiceUnavailable),
+		Backoff(time.Millisecond, 5*time.Millisecond),
+		Jitter(0.5),
+		OnRetry(func(uint, error) { atomic.AddInt32(&retries, 1) }),
+		Debug(&debug),
+	)
+	var wg sync.WaitGroup
+	errs := make(chan error, 200*3)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("/flaky/%d", i%20)
+			if i%2 == 0 {
+				path = fmt.Sprintf("/ok/%d", i%10)
+			}
+			var dest struct{ Path string }
+			if err := reader.JSON(srv.URL+path, &dest); err != nil || dest.Path != path {
+				errs <- fmt.Errorf("JSON(%s) = %+v, %v", path, dest, err)
+			}
+			if b, err := reader.Bytes(srv.URL + path); err != nil || !bytes.Contains(b, []byte(path)) {
+				errs <- fmt.Errorf("Bytes(%s) = %s, %v", path, b, err)
+			}
+			if s, err := reader.String(srv.URL + "/query?" + path); err != nil || !strings.Contains(s, "/query") {
+				errs <- fmt.Errorf("String(%s) = %s, %v", path, s, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 4 {
+		t.Errorf("%d requests are in flight at once, limit is 4", max)
+	}
+	if atomic.LoadInt32(&retries) == 0 {
+		t.Error("no request is retried")
+	}
+	if debug.Len() == 0 {
+		t.Error("nothing is dumped")
+	}
+}
+
+func TestRetryErrorOfUnrewindableBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(ioutil.Discard, req.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("busy"))
+	}))
+	defer srv.Close()
+	reader := NewReader(Retry(2), RetryNonIdempotent(), RetryOnStatus(http.StatusServiceUnavailable))
+	// a stream larger than the limit of buffering can't be sent again
+	body := io.MultiReader(bytes.NewReader(make([]byte, rewindLimit+1)))
+	_, err := reader.Post(srv.URL, "application/octet-stream", body)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Post() = %v", err)
+	}
+	if string(httpErr.Body) != "busy" {
+		t.Errorf("error has body %q", httpErr.Body)
+	}
+}
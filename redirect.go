@@ -0,0 +1,52 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxRedirects is the number of redirects followed by default, same as net/http
+const defaultMaxRedirects = 10
+
+// MaxRedirects option for remote reader limits the number of redirects followed by a request
+// Defaults to 10
+func MaxRedirects(n int) Option { return func(r *Reader) { r.maxRedirects = n } }
+
+// DisableRedirects option for remote reader doesn't follow redirects
+// returning the redirect response itself instead
+func DisableRedirects() Option { return func(r *Reader) { r.maxRedirects = 0 } }
+
+// PreserveAuthOnRedirect option for remote reader keeps the Authorization header
+// when redirected to given domains or their subdomains, which net/http drops for other hosts
+// Header is never sent over plain http once the request started over https
+func PreserveAuthOnRedirect(domains ...string) Option {
+	return func(r *Reader) { r.authDomains = append(r.authDomains, domains...) }
+}
+
+// checkRedirect is the redirect policy of the client built by the reader
+func (r *Reader) checkRedirect(req *http.Request, via []*http.Request) error {
+	if r.maxRedirects <= 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= r.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", r.maxRedirects)
+	}
+	first := via[0]
+	auth, ok := first.Header["Authorization"]
+	if !ok || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+	if req.URL.Scheme != "https" && first.URL.Scheme == "https" {
+		return nil
+	}
+	host := strings.ToLower(req.URL.Hostname())
+	for _, domain := range r.authDomains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			req.Header["Authorization"] = auth
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// MaxConcurrent option for remote reader limits the number of requests in flight at once
+// A request holds its slot until its response body is closed
+func MaxConcurrent(n int) Option {
+	return func(r *Reader) {
+		if n <= 0 {
+			r.slots = nil
+			return
+		}
+		r.slots = make(semaphore, n)
+	}
+}
+
+// semaphore limits concurrent requests, nil means no limit
+type semaphore chan struct{}
+
+// acquire waits for a free slot unless the context is done before
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseOnClose releases the slot of a request once its response body is closed
+// or right away if the request failed
+func (s semaphore) releaseOnClose(resp *http.Response, err error) {
+	if s == nil {
+		return
+	}
+	if err != nil || resp == nil {
+		<-s
+		return
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-s }}
+}
+
+// releaseBody calls release once when the body is closed
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// cancelOnClose cancels the context of a request once its response body is closed
+// or right away if the request failed
+func cancelOnClose(resp *http.Response, err error, cancel context.CancelFunc) {
+	if err != nil || resp == nil {
+		cancel()
+		return
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: cancel}
+}
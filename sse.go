@@ -0,0 +1,140 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a server-sent event
+type Event struct {
+	// ID is the last event id sent by the server
+	ID string
+	// Event is the type of the event, "message" if not set by the server
+	Event string
+	// Data is the data of the event, multiple data lines are joined by new lines
+	Data string
+}
+
+// Events reads server-sent events from given url calling the handler for every event until the context is done
+// Connection is kept open and reestablished with Last-Event-ID header when the server closes it
+// Reconnects wait as configured by Backoff or the retry field sent by the server,
+// and give up after as many consecutive reconnects without any event as configured by Retry,
+// including failed ones, e.g. while the server restarts. Failing to connect at first is returned right away
+// Timeout and OverallTimeout don't apply as the stream is read as long as it's open
+func (r *Reader) Events(ctx context.Context, url string, handler func(Event)) error {
+	r = r.forURL(url)
+	stream := r.streaming()
+	header := http.Header{"Accept": {"text/event-stream"}, "Cache-Control": {"no-cache"}}
+	state := &eventState{}
+	var reconnects uint
+	connected := false
+	for {
+		if state.id != "" {
+			header.Set("Last-Event-ID", state.id)
+		}
+		resp, err := stream.do(ctx, http.MethodGet, url, header, nil)
+		if err == nil && !stream.isSuccess(resp.StatusCode) {
+			err = r.statusError(resp, url)
+			resp.Body.Close()
+		}
+		if err != nil && !connected {
+			return err
+		}
+		received := false
+		if err == nil {
+			connected = true
+			received, err = state.read(resp.Body, handler)
+			resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			reconnects = 0
+		}
+		if reconnects >= r.retry {
+			if err == nil {
+				err = io.EOF
+			}
+			return wrapError(err, "event stream is disconnected")
+		}
+		delay := state.retry
+		if delay <= 0 {
+			delay = r.backoff(reconnects)
+		}
+		if err = sleep(ctx, delay); err != nil {
+			return err
+		}
+		reconnects++
+	}
+}
+
+// streaming returns a copy of the reader without any timeout of a whole request for reading long lived bodies
+func (r *Reader) streaming() *Reader {
+	client := *r.client
+	client.Timeout = 0
+	stream := *r
+	stream.client = &client
+	stream.overallTimeout = 0
+	return &stream
+}
+
+// eventState is the state of an event stream kept between reconnects
+type eventState struct {
+	id    string
+	retry time.Duration
+}
+
+// read parses server-sent events from given body calling the handler for every event
+// Returns whether any event is received until the body ends
+func (s *eventState) read(body io.Reader, handler func(Event)) (bool, error) {
+	var received bool
+	var event string
+	var data strings.Builder
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// blank line dispatches the event
+			if data.Len() > 0 {
+				if event == "" {
+					event = "message"
+				}
+				handler(Event{ID: s.id, Event: event, Data: strings.TrimSuffix(data.String(), "\n")})
+				received = true
+			}
+			event = ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.id = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return received, scanner.Err()
+}
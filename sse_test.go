@@ -0,0 +1,90 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventsIgnoreTimeouts(t *testing.T) {
+	var connections int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(w, "data: %d\n\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	tests := map[string][]Option{
+		"overall timeout": {OverallTimeout(100 * time.Millisecond)},
+		"within deadline": {Timeout(100 * time.Millisecond), WithRetryMode(RetryWithinDeadline)},
+		"host config":     {HostConfig(u.Host, OverallTimeout(100*time.Millisecond))},
+	}
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&connections, 0)
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			var last string
+			err := NewReader(options...).Events(ctx, srv.URL, func(event Event) {
+				if last = event.Data; last == "9" {
+					cancel()
+				}
+			})
+			if last != "9" || err != context.Canceled {
+				t.Errorf("Events() = %v, last event %q", err, last)
+			}
+			if n := atomic.LoadInt32(&connections); n != 1 {
+				t.Errorf("stream is reconnected, %d connections", n)
+			}
+		})
+	}
+}
+
+func TestEventsReconnectFailures(t *testing.T) {
+	tests := map[string]struct {
+		failures int32
+		ok       bool
+	}{
+		"server restarts": {2, true},
+		"server is gone":  {100, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var connections int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				n := atomic.AddInt32(&connections, 1)
+				if n > 1 && n <= 1+tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprintf(w, "id: %d\ndata: %d\n\n", n, n)
+			}))
+			defer srv.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			var events []string
+			err := NewReader(Retry(3), Backoff(time.Millisecond, time.Millisecond)).Events(ctx, srv.URL, func(event Event) {
+				if events = append(events, event.Data); len(events) == 2 {
+					cancel()
+				}
+			})
+			if tt.ok && (err != context.Canceled || len(events) != 2 || events[1] != "4") {
+				t.Errorf("Events() = %v, events %v", err, events)
+			}
+			if !tt.ok && (err == nil || err == context.Canceled || len(events) != 1) {
+				t.Errorf("Events() = %v, events %v", err, events)
+			}
+		})
+	}
+}
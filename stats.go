@@ -0,0 +1,28 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Stats describes how a request went across its attempts
+type Stats struct {
+	// Attempts is the number of requests actually sent, retries included
+	Attempts int
+	// TotalDuration is the time spent on all attempts and the delays between them
+	TotalDuration time.Duration
+	// StatusCode is the status of the final response or 0 if there was none
+	StatusCode int
+}
+
+// ReadWithStats returns response from given url with configured reader along with stats of the attempts made
+// Stats are filled even if an error is returned
+func (r *Reader) ReadWithStats(url string) (*http.Response, Stats, error) {
+	return r.ReadWithStatsWithContext(context.Background(), url)
+}
+
+// ReadWithStatsWithContext returns response from given url with configured reader using given context along with stats of the attempts made
+func (r *Reader) ReadWithStatsWithContext(ctx context.Context, url string) (*http.Response, Stats, error) {
+	return r.doWithStats(ctx, http.MethodGet, url, nil, nil)
+}
@@ -0,0 +1,151 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Stream returns the body of given url with configured reader for the caller to read and close
+// Retries only cover establishing the request up to a success status, failures while reading the body
+// are returned by the reader as is. Body is decompressed and limited by MaxBytes like Bytes,
+// Timeout still applies to reading it so it may need to be raised for long streams
+func (r *Reader) Stream(url string) (io.ReadCloser, error) {
+	return r.StreamWithContext(context.Background(), url)
+}
+
+// StreamWithContext returns the body of given url with configured reader using given context
+// for the caller to read and close
+func (r *Reader) StreamWithContext(ctx context.Context, url string) (io.ReadCloser, error) {
+	r = r.forURL(url)
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	r.trackProgress(resp)
+	body, err := r.body(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{body, resp.Body}, nil
+}
+
+// JSONLines reads newline delimited json from given url with configured reader
+// calling the handler for every line as it arrives, without buffering the whole body
+// Empty lines are skipped, MaxBytes option limits the size of a single line instead of the body
+// Stops at the first error returned by the handler and returns it
+func (r *Reader) JSONLines(url string, handler func(json.RawMessage) error) error {
+	return r.JSONLinesWithContext(context.Background(), url, handler)
+}
+
+// JSONLinesWithContext reads newline delimited json from given url with configured reader using given context
+// calling the handler for every line as it arrives
+func (r *Reader) JSONLinesWithContext(ctx context.Context, url string, handler func(json.RawMessage) error) error {
+	r = r.forURL(url)
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := r.decompress(resp)
+	if err != nil {
+		return err
+	}
+	maxLine := math.MaxInt32
+	if r.maxBytes > 0 && r.maxBytes < math.MaxInt32 {
+		maxLine = int(r.maxBytes)
+	}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxLine)
+	for line := 1; scanner.Scan(); line++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		if !json.Valid(b) {
+			return fmt.Errorf("can't decode json line %d", line)
+		}
+		// scanner reuses its buffer so the handler gets a copy
+		if err = handler(json.RawMessage(append([]byte(nil), b...))); err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err == bufio.ErrTooLong {
+		return ErrBodyTooLarge
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return wrapError(err, "can't read json lines")
+}
+
+// JSONStream reads a json array from given url with configured reader
+// decoding its elements one at a time into the values returned by newElem and calling the handler for each
+// so memory stays flat for large arrays, MaxBytes option still limits the whole body
+// Stops at the first error returned by the handler and returns it
+func (r *Reader) JSONStream(url string, newElem func() interface{}, handler func(interface{}) error) error {
+	return r.JSONStreamWithContext(context.Background(), url, newElem, handler)
+}
+
+// JSONStreamWithContext reads a json array from given url with configured reader using given context
+// decoding its elements one at a time and calling the handler for each
+func (r *Reader) JSONStreamWithContext(ctx context.Context, url string, newElem func() interface{},
+	handler func(interface{}) error) error {
+	r = r.forURL(url)
+	resp, err := r.readOK(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := r.body(resp)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(body)
+	if err = expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for i := 0; dec.More(); i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		elem := newElem()
+		if err = dec.Decode(elem); err != nil {
+			if errors.Is(err, ErrBodyTooLarge) {
+				return ErrBodyTooLarge
+			}
+			return wrapErrorf(err, "can't decode json array element %d", i)
+		}
+		if err = handler(elem); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token of the decoder failing unless it's given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err == ErrBodyTooLarge {
+		return err
+	}
+	if err != nil {
+		return wrapError(err, "can't decode json array")
+	}
+	if token != delim {
+		return fmt.Errorf("can't decode json array: got %v instead of %v", token, delim)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTrace is the latency breakdown of a request attempt
+// Phases which didn't happen, e.g. DNS and connect on a reused connection, are zero
+type RequestTrace struct {
+	URL     string
+	Attempt uint
+	// DNS is the time spent resolving the host
+	DNS time.Duration
+	// Connect is the time spent establishing the TCP connection
+	Connect time.Duration
+	// TLSHandshake is the time spent on the TLS handshake
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the time from the start of the attempt until the first byte of the response
+	TimeToFirstByte time.Duration
+	// Total is the time from the start of the attempt until response headers are read
+	Total time.Duration
+	// ReusedConn is true if an idle connection was reused
+	ReusedConn bool
+	// Err is the error of the attempt if it failed
+	Err error
+}
+
+// WithTrace option for remote reader calls given function after every request attempt
+// with the latency breakdown of it
+func WithTrace(onTrace func(*RequestTrace)) Option { return func(r *Reader) { r.onTrace = onTrace } }
+
+// tracer gathers a RequestTrace, hooks can be called concurrently e.g. dialing both IPv4 and IPv6
+type tracer struct {
+	mu           sync.Mutex
+	trace        RequestTrace
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+}
+
+// startTrace attaches a client trace to the context if tracing is configured
+// Returned function finishes the trace with the error of the attempt
+func (r *Reader) startTrace(ctx context.Context, url string, attempt uint) (context.Context, func(error)) {
+	if r.onTrace == nil {
+		return ctx, func(error) {}
+	}
+	t := &tracer{trace: RequestTrace{URL: url, Attempt: attempt}, start: time.Now()}
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.trace.ReusedConn = info.Reused
+			t.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.trace.DNS = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			t.trace.Connect = time.Since(t.connectStart)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.trace.TLSHandshake = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.trace.TimeToFirstByte = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	})
+	return ctx, func(err error) {
+		t.mu.Lock()
+		trace := t.trace
+		t.mu.Unlock()
+		trace.Total = time.Since(t.start)
+		trace.Err = err
+		r.onTrace(&trace)
+	}
+}
@@ -0,0 +1,274 @@
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Proxy option for remote reader sends all requests through given proxy
+// instead of the one configured by environment variables
+func Proxy(proxyURL string) Option {
+	return func(r *Reader) {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			r.err = wrapError(err, "can't parse proxy url")
+			return
+		}
+		r.proxy = http.ProxyURL(u)
+	}
+}
+
+// NoProxy option for remote reader connects directly ignoring proxy environment variables
+func NoProxy() Option {
+	return func(r *Reader) {
+		r.proxy = func(*http.Request) (*url.URL, error) { return nil, nil }
+	}
+}
+
+// ClientCert option for remote reader presents the certificate loaded from given PEM files for mutual TLS
+// Loading error is returned by the calls of the reader
+func ClientCert(certFile, keyFile string) Option {
+	return func(r *Reader) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			r.err = wrapError(err, "can't load client certificate")
+			return
+		}
+		r.certificates = append(r.certificates, cert)
+	}
+}
+
+// ClientCertificate option for remote reader presents given certificate for mutual TLS
+func ClientCertificate(cert tls.Certificate) Option {
+	return func(r *Reader) { r.certificates = append(r.certificates, cert) }
+}
+
+// RootCAs option for remote reader trusts the certificate authorities in given PEM data
+// instead of the system pool, so private CAs can be used without SkipTLSVerify
+func RootCAs(pemData []byte) Option {
+	return func(r *Reader) {
+		if r.rootCAs == nil {
+			r.rootCAs = x509.NewCertPool()
+		}
+		if !r.rootCAs.AppendCertsFromPEM(pemData) {
+			r.err = errors.New("can't parse root CA certificates")
+		}
+	}
+}
+
+// RootCAFile option for remote reader trusts the certificate authorities in the PEM file at given path
+func RootCAFile(path string) Option {
+	return func(r *Reader) {
+		pemData, err := ioutil.ReadFile(path)
+		if err != nil {
+			r.err = wrapError(err, "can't read root CA file")
+			return
+		}
+		RootCAs(pemData)(r)
+	}
+}
+
+// MinTLSVersion option for remote reader rejects servers not supporting at least given TLS version
+// e.g. tls.VersionTLS12, defaults to the minimum of crypto/tls
+func MinTLSVersion(version uint16) Option { return func(r *Reader) { r.minTLSVersion = version } }
+
+// DialTimeout option for remote reader limits the time to establish a connection
+// Together with ResponseHeaderTimeout it allows failing fast on unreachable or unresponsive hosts
+// while Timeout still bounds the whole request including reading the body
+// Defaults to 30 seconds like the default transport of net/http, still bounded by Timeout
+func DialTimeout(timeout time.Duration) Option { return func(r *Reader) { r.dialTimeout = timeout } }
+
+// ResponseHeaderTimeout option for remote reader limits the time to wait for response headers
+// after the request is written, slow bodies are not affected. Defaults to no limit other than Timeout
+func ResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(r *Reader) { r.responseHeaderTimeout = timeout }
+}
+
+// MaxIdleConns option for remote reader limits the number of idle connections kept open across all hosts
+// Defaults to 100 like the default transport of net/http, 0 keeps the default
+func MaxIdleConns(n int) Option { return func(r *Reader) { r.maxIdleConns = n } }
+
+// MaxIdleConnsPerHost option for remote reader limits the number of idle connections kept open per host
+// Defaults to 2 like net/http which is a bottleneck for many concurrent requests to the same host
+func MaxIdleConnsPerHost(n int) Option { return func(r *Reader) { r.maxIdleConnsPerHost = n } }
+
+// IdleConnTimeout option for remote reader closes connections which are idle longer than given duration
+// Defaults to 90 seconds like the default transport of net/http
+func IdleConnTimeout(timeout time.Duration) Option {
+	return func(r *Reader) { r.idleConnTimeout = timeout }
+}
+
+// DisableKeepAlives option for remote reader closes every connection after its request
+// Pooled connections save handshakes on repeated requests to the same hosts, which is the default,
+// while one-off requests to many distinct hosts, e.g. scanners, only waste resources keeping them open
+func DisableKeepAlives() Option { return func(r *Reader) { r.disableKeepAlives = true } }
+
+// UnixSocket option for remote reader connects to the unix domain socket at given path for all requests
+// e.g. for the Docker daemon, host of the url is ignored for dialing while its path is requested as usual
+func UnixSocket(path string) Option { return func(r *Reader) { r.unixSocket = path } }
+
+// Resolver option for remote reader resolves host names with given resolver instead of the default one
+func Resolver(resolver *net.Resolver) Option { return func(r *Reader) { r.resolver = resolver } }
+
+// HostOverride option for remote reader connects to the address given for a host instead of resolving it
+// e.g. {"example.com": "10.0.0.1"} to test against a staging server, a port can be given too
+// Only dialing is affected, TLS server name and Host header are still of the url
+func HostOverride(overrides map[string]string) Option {
+	return func(r *Reader) {
+		if r.hostOverrides == nil {
+			r.hostOverrides = make(map[string]string, len(overrides))
+		}
+		for host, addr := range overrides {
+			r.hostOverrides[host] = addr
+		}
+	}
+}
+
+// LocalAddr option for remote reader sends requests from given local IP address, e.g. "192.0.2.1",
+// for egress allowlists on hosts with several interfaces, a port may be given too
+// An invalid address is returned by the calls of the reader
+func LocalAddr(addr string) Option {
+	return func(r *Reader) {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "0")
+		}
+		tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+		if err != nil {
+			r.err = wrapErrorf(err, "can't resolve local address %q", addr)
+			return
+		}
+		if tcpAddr.IP == nil {
+			r.err = fmt.Errorf("can't use local address %q without an IP", addr)
+			return
+		}
+		r.localAddr = tcpAddr
+	}
+}
+
+// HostHeader option for remote reader sends given Host header instead of the host of the url
+// while still connecting to the latter, e.g. to test virtual hosts or a CDN edge
+// A Host set on a request given to Do takes precedence
+func HostHeader(host string) Option { return func(r *Reader) { r.hostHeader = host } }
+
+// ForceHTTP1 option for remote reader disables HTTP/2 so HTTP/1.1 is used even over TLS
+// e.g. to get along with intermediaries having a buggy HTTP/2 support
+func ForceHTTP1() Option {
+	return func(r *Reader) {
+		r.forceHTTP1 = true
+		r.h2c = false
+	}
+}
+
+// EnableH2C option for remote reader uses HTTP/2 over cleartext connections for http urls
+// without falling back to HTTP/1.1, so the server needs to support prior knowledge h2c
+func EnableH2C() Option {
+	return func(r *Reader) {
+		r.h2c = true
+		r.forceHTTP1 = false
+	}
+}
+
+// newRoundTripper creates the round tripper of the default client wrapped by middlewares
+func (r *Reader) newRoundTripper() http.RoundTripper {
+	if r.roundTripper != nil {
+		return r.wrap(r.roundTripper)
+	}
+	return r.wrap(r.newTransport())
+}
+
+// newTransport creates the transport shared by all requests of the reader
+// so connections are pooled and kept alive between calls
+func (r *Reader) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = r.newTLSConfig()
+	if r.proxy != nil {
+		transport.Proxy = r.proxy
+	}
+	if r.unixSocket != "" {
+		// proxies can't be reached through the socket
+		transport.Proxy = nil
+	}
+	transport.DialContext = r.dialContext(r.newDialer())
+	transport.ResponseHeaderTimeout = r.responseHeaderTimeout
+	if r.maxIdleConns > 0 {
+		transport.MaxIdleConns = r.maxIdleConns
+	}
+	if r.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = r.maxIdleConnsPerHost
+	}
+	if r.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = r.idleConnTimeout
+	}
+	transport.DisableKeepAlives = r.disableKeepAlives
+	if r.forceHTTP1 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if r.h2c {
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return transport
+}
+
+// newTLSConfig creates the TLS configuration of the transport
+func (r *Reader) newTLSConfig() *tls.Config {
+	return &tls.Config{
+		/* #nosec */
+		InsecureSkipVerify: r.skipTLSVerify,
+		Certificates:       r.certificates,
+		RootCAs:            r.rootCAs,
+		MinVersion:         r.minTLSVersion,
+	}
+}
+
+// newDialer creates the dialer used by the transport for new connections
+func (r *Reader) newDialer() *net.Dialer {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if r.dialTimeout > 0 {
+		dialer.Timeout = r.dialTimeout
+	}
+	dialer.Resolver = r.resolver
+	if r.localAddr != nil {
+		dialer.LocalAddr = r.localAddr
+	}
+	return dialer
+}
+
+// dialContext returns the function used by the transport to dial given address with given dialer
+func (r *Reader) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if r.unixSocket != "" {
+			// a local TCP address can't be used for a unix socket
+			unixDialer := *dialer
+			unixDialer.LocalAddr = nil
+			return unixDialer.DialContext(ctx, "unix", r.unixSocket)
+		}
+		return dialer.DialContext(ctx, network, r.overrideAddr(addr))
+	}
+}
+
+// overrideAddr returns the address to dial instead of given one if its host is overridden
+func (r *Reader) overrideAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	override, ok := r.hostOverrides[host]
+	if !ok {
+		return addr
+	}
+	if _, _, err := net.SplitHostPort(override); err == nil {
+		return override
+	}
+	return net.JoinHostPort(override, port)
+}
@@ -0,0 +1,123 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// UploadProgress option for remote reader calls given function while Upload sends the files
+// with the number of file bytes sent so far and the total size of the files
+func UploadProgress(progress func(sent, total int64)) Option {
+	return func(r *Reader) { r.uploadProgress = progress }
+}
+
+// Upload posts given files and fields as multipart/form-data to the url with configured reader
+// Files map form field names to paths, they are streamed from disk instead of being read into memory
+// Uploads larger than 1 MB are streamed only once so they aren't retried even with RetryNonIdempotent
+func (r *Reader) Upload(url string, files, fields map[string]string) (*http.Response, error) {
+	return r.UploadWithContext(context.Background(), url, files, fields)
+}
+
+// UploadWithContext posts given files and fields as multipart/form-data to the url with configured reader
+// using given context
+func (r *Reader) UploadWithContext(ctx context.Context, url string, files, fields map[string]string) (*http.Response, error) {
+	r = r.forURL(url)
+	parts, total, err := openParts(files)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		defer closeParts(parts)
+		pw.CloseWithError(r.writeMultipart(mw, parts, fields, total))
+	}()
+	return r.PostWithContext(ctx, url, mw.FormDataContentType(), pr)
+}
+
+// part is a file to upload under a form field
+type part struct {
+	field string
+	file  *os.File
+}
+
+// openParts opens given files sorted by their field names and returns their total size
+func openParts(files map[string]string) ([]part, int64, error) {
+	fields := make([]string, 0, len(files))
+	for field := range files {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	parts := make([]part, 0, len(fields))
+	var total int64
+	for _, field := range fields {
+		f, err := os.Open(files[field])
+		if err != nil {
+			closeParts(parts)
+			return nil, 0, wrapError(err, "can't open file to upload")
+		}
+		parts = append(parts, part{field: field, file: f})
+		info, err := f.Stat()
+		if err != nil {
+			closeParts(parts)
+			return nil, 0, wrapError(err, "can't stat file to upload")
+		}
+		total += info.Size()
+	}
+	return parts, total, nil
+}
+
+func closeParts(parts []part) {
+	for _, p := range parts {
+		p.file.Close()
+	}
+}
+
+// writeMultipart writes fields and then files as multipart form
+func (r *Reader) writeMultipart(mw *multipart.Writer, parts []part, fields map[string]string, total int64) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := mw.WriteField(name, fields[name]); err != nil {
+			return wrapError(err, "can't write form field")
+		}
+	}
+	var sent int64
+	for _, p := range parts {
+		w, err := mw.CreateFormFile(p.field, filepath.Base(p.file.Name()))
+		if err != nil {
+			return wrapError(err, "can't create form file")
+		}
+		if r.uploadProgress != nil {
+			w = &progressWriter{Writer: w, written: &sent, total: total, progress: r.uploadProgress}
+		}
+		if _, err = io.Copy(w, p.file); err != nil {
+			return wrapError(err, "can't write form file")
+		}
+	}
+	return wrapError(mw.Close(), "can't close multipart form")
+}
+
+// progressWriter reports the number of bytes written so far after every write
+type progressWriter struct {
+	io.Writer
+	written  *int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	*w.written += int64(n)
+	w.progress(*w.written, w.total)
+	return n, err
+}
@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Validate option for remote reader checks every body read by Bytes, JSON and alike with given function
+// e.g. against a checksum header or a schema, an error fails the call unless it's marked by Retryable
+// in which case the body is read again as configured by Retry and Backoff options
+// Bodies are read into memory before decoding when a validation is configured
+func Validate(validate func(resp *http.Response, body []byte) error) Option {
+	return func(r *Reader) { r.validate = validate }
+}
+
+// Retryable marks given error returned by the function of Validate option to read the body again
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+// retryableError is an error which is worth reading the body again
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// isRetryable checks if given error is marked by Retryable anywhere in the chain
+func isRetryable(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
+// fetchValid reads the whole body of given url until it passes the validation or a retry isn't possible
+func (r *Reader) fetchValid(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	began := time.Now()
+	for i := uint(0); ; i++ {
+		b, resp, err := r.readBytes(ctx, url)
+		if err != nil {
+			return b, resp, err
+		}
+		if err = r.validate(resp, b); err == nil {
+			return b, resp, nil
+		}
+		err = wrapError(err, "invalid response")
+		if !isRetryable(err) || i+1 >= r.retry {
+			return nil, resp, err
+		}
+		delay := r.delay(i, resp)
+		if exceedsDeadline(ctx, delay) || r.exceedsBudget(began, delay) {
+			return nil, resp, err
+		}
+		if r.onRetry != nil {
+			r.onRetry(i+1, err)
+		}
+		if ctxErr := sleep(ctx, delay); ctxErr != nil {
+			return nil, resp, ctxErr
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build yaml
+// +build yaml
+
+package remote
+
+import (
+	"context"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"} {
+		decoders[mediaType] = DecodeAsYAML
+	}
+}
+
+// YAML reads bytes from given url with configured reader and decodes body into the destination
+// YAML support is only built with the yaml build tag not to force gopkg.in/yaml.v3 on everyone
+func (r *Reader) YAML(url string, dest interface{}) error {
+	return r.YAMLWithContext(context.Background(), url, dest)
+}
+
+// YAMLWithContext reads bytes from given url with configured reader using given context
+// and decodes body into the destination
+func (r *Reader) YAMLWithContext(ctx context.Context, url string, dest interface{}) error {
+	return r.decode(ctx, url, dest, DecodeAsYAML)
+}
+
+// DecodeAsYAML decodes given reader into destination
+// assuming content is yaml
+func DecodeAsYAML(r io.Reader, dest interface{}) error {
+	err := yaml.NewDecoder(r).Decode(dest)
+	if err == io.EOF {
+		return nil
+	}
+	return wrapError(err, "can't decode yaml")
+}